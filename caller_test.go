@@ -0,0 +1,59 @@
+package log
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func callerLine(t *testing.T) string {
+	t.Helper()
+
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf(" %s:%d ", filepath.Base(file), line+1)
+}
+
+func TestCallerAttribution(t *testing.T) {
+	keepGlobal(t)
+
+	config, file := newTestConfig(t)
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+	l, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want []string
+	want = append(want, callerLine(t))
+	Infof("package function")
+	want = append(want, callerLine(t))
+	Infow("package w function")
+	want = append(want, callerLine(t))
+	Println("package ln function")
+	want = append(want, callerLine(t))
+	l.Infof("config logger")
+	want = append(want, callerLine(t))
+	getLogger().Named("child").Infof("global logger method")
+	Fields("k", "v")
+	want = append(want, callerLine(t))
+	Warnf("after Fields")
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("expect %d entries, got %q", len(want), data)
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, want[i]) {
+			t.Errorf("entry %d: expect caller %q in %q", i, want[i], line)
+		}
+	}
+}
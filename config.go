@@ -0,0 +1,353 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ehlxr/lumberjack"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig reads a YAML, JSON or TOML file (chosen by extension) and
+// overlays it on top of NewLogConfig(). Unknown keys and invalid level names
+// are reported as errors.
+func LoadConfig(file string) (*Config, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read log config file error. %w", err)
+	}
+
+	raw := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(file)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported log config format %q of %s", ext, file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse log config %s error. %w", file, err)
+	}
+
+	config := NewLogConfig()
+	if err := config.decode(raw); err != nil {
+		return nil, fmt.Errorf("parse log config %s error. %w", file, err)
+	}
+
+	return config, nil
+}
+
+// decode applies raw, as produced by any of the supported formats, to config.
+// All formats are funneled through encoding/json so key matching and unknown
+// key detection behave the same regardless of the file type.
+func (config *Config) decode(raw map[string]interface{}) error {
+	if v, ok := raw["fields"]; ok {
+		fields, err := decodeFields(v)
+		if err != nil {
+			return err
+		}
+		config.Fields = fields
+		delete(raw, "fields")
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	return dec.Decode(config)
+}
+
+func decodeFields(v interface{}) ([]zap.Field, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("fields must be a map of key to value, got %T", v)
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]zap.Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, zap.Any(k, m[k]))
+	}
+
+	return fields, nil
+}
+
+// configVar binds one configuration key to a field of a Config, so that the
+// same set of keys can be fed from environment variables and command-line flags.
+type configVar struct {
+	key   string
+	flag  string
+	usage string
+	value flag.Value
+}
+
+func (config *Config) vars() []configVar {
+	if config.Logger == nil {
+		config.Logger = &lumberjack.Logger{LocalTime: true}
+	}
+
+	return []configVar{
+		{key: "level", usage: "minimum enabled logging level", value: &config.Level},
+		{key: "levels", usage: "logger name level overrides, e.g. db=warn,http=debug,*=info", value: (*levelsValue)(&config.Levels)},
+		{key: "enableColors", usage: "colorize the level text", value: (*boolValue)(&config.EnableColors)},
+		{key: "crashLogFilename", flag: "crash-file", usage: "file that receives the process stderr", value: (*stringValue)(&config.CrashLogFilename)},
+		{key: "errorLogFilename", flag: "error-file", usage: "file that receives error and above entries", value: (*stringValue)(&config.ErrorLogFilename)},
+		{key: "enableLineNumber", usage: "annotate entries with the caller file and line", value: (*boolValue)(&config.EnableLineNumber)},
+		{key: "addCallerSkip", usage: "number of extra caller frames to skip for helpers wrapping the logger", value: (*intValue)(&config.AddCallerSkip)},
+		{key: "enableLevelTruncation", usage: "truncate the level text to 4 characters", value: (*boolValue)(&config.EnableLevelTruncation)},
+		{key: "enableErrorStacktrace", usage: "record a stacktrace for error and above entries", value: (*boolValue)(&config.EnableErrorStacktrace)},
+		{key: "timestampFormat", usage: "time layout of the entry timestamp", value: (*stringValue)(&config.TimestampFormat)},
+		{key: "enableCapitalLevel", usage: "use capital letters for the level text", value: (*boolValue)(&config.EnableCapitalLevel)},
+		{key: "enableFullCaller", usage: "annotate entries with the full path of the caller", value: (*boolValue)(&config.EnableFullCaller)},
+		{key: "development", usage: "development mode, DPanic entries panic", value: (*boolValue)(&config.Development)},
+		{key: "dedup", usage: "window collapsing the repeated entries of the default destinations, e.g. 1s", value: (*durationValue)(&config.Dedup)},
+		{key: "name", usage: "logger name", value: (*stringValue)(&config.Name)},
+		{key: "fields", usage: "comma separated key=value pairs added to every entry", value: (*fieldsValue)(&config.Fields)},
+		{key: "filename", flag: "file", usage: "file to write logs to", value: (*stringValue)(&config.Filename)},
+		{key: "maxSize", usage: "maximum size in megabytes of a log file before it gets rotated", value: (*intValue)(&config.MaxSize)},
+		{key: "maxAge", usage: "maximum number of days to retain old log files", value: (*intValue)(&config.MaxAge)},
+		{key: "maxBackups", usage: "maximum number of old log files to retain", value: (*intValue)(&config.MaxBackups)},
+		{key: "localTime", usage: "use local time in backup file names", value: (*boolValue)(&config.LocalTime)},
+		{key: "compress", usage: "gzip rotated log files", value: (*boolValue)(&config.Compress)},
+		{key: "backupTimeFormat", usage: "time layout appended to backup file names", value: (*stringValue)(&config.BackupTimeFormat)},
+	}
+}
+
+// splitKey splits a camelCase key into its lower case words.
+func splitKey(key string) []string {
+	var (
+		words []string
+		start int
+	)
+	for i, r := range key {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, strings.ToLower(key[start:i]))
+			start = i
+		}
+	}
+
+	return append(words, strings.ToLower(key[start:]))
+}
+
+type stringValue string
+
+func (s *stringValue) Set(v string) error {
+	*s = stringValue(v)
+	return nil
+}
+
+func (s *stringValue) String() string { return string(*s) }
+
+type durationValue Duration
+
+func (d *durationValue) Set(v string) error {
+	return (*Duration)(d).UnmarshalText([]byte(v))
+}
+
+func (d *durationValue) String() string { return time.Duration(*d).String() }
+
+type levelsValue string
+
+func (s *levelsValue) Set(v string) error {
+	if _, err := parseLevelSpec(v); err != nil {
+		return err
+	}
+	*s = levelsValue(v)
+	return nil
+}
+
+func (s *levelsValue) String() string { return string(*s) }
+
+type boolValue bool
+
+func (b *boolValue) Set(v string) error {
+	p, err := strconv.ParseBool(v)
+	if err != nil {
+		return err
+	}
+	*b = boolValue(p)
+	return nil
+}
+
+func (b *boolValue) String() string { return strconv.FormatBool(bool(*b)) }
+
+func (b *boolValue) IsBoolFlag() bool { return true }
+
+type intValue int
+
+func (i *intValue) Set(v string) error {
+	p, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+	*i = intValue(p)
+	return nil
+}
+
+func (i *intValue) String() string { return strconv.Itoa(int(*i)) }
+
+type fieldsValue []zap.Field
+
+func (f *fieldsValue) Set(v string) error {
+	var fields []zap.Field
+	for _, pair := range strings.Split(v, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("invalid field %q, expect key=value", pair)
+		}
+		fields = append(fields, zap.String(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])))
+	}
+	*f = fields
+	return nil
+}
+
+func (f *fieldsValue) String() string {
+	if f == nil {
+		return ""
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	pairs := make([]string, 0, len(*f))
+	for _, field := range *f {
+		field.AddTo(enc)
+		pairs = append(pairs, fmt.Sprintf("%s=%v", field.Key, enc.Fields[field.Key]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Validate checks the whole config and reports every problem found: an unknown
+// level, an empty TimestampFormat, negative rotation settings, malformed
+// Outputs and log files that cannot be created or opened for writing. It
+// creates neither the log files nor their directories.
+func (config *Config) Validate() error {
+	errs := config.validateLogger()
+
+	if config.Logger != nil {
+		if config.MaxSize < 0 {
+			errs = multierr.Append(errs, fmt.Errorf("invalid maxSize %d, must not be negative", config.MaxSize))
+		}
+		if config.MaxAge < 0 {
+			errs = multierr.Append(errs, fmt.Errorf("invalid maxAge %d, must not be negative", config.MaxAge))
+		}
+		if config.MaxBackups < 0 {
+			errs = multierr.Append(errs, fmt.Errorf("invalid maxBackups %d, must not be negative", config.MaxBackups))
+		}
+		if len(config.Outputs) == 0 {
+			errs = multierr.Append(errs, checkWritable("filename", config.Filename))
+		}
+	}
+
+	if config.Dedup < 0 {
+		errs = multierr.Append(errs, fmt.Errorf("invalid dedup %s, must not be negative", time.Duration(config.Dedup)))
+	}
+
+	if len(config.Outputs) == 0 {
+		errs = multierr.Append(errs, checkWritable("errorLogFilename", config.ErrorLogFilename))
+	}
+	for i, output := range config.Outputs {
+		errs = multierr.Append(errs, output.validate(i))
+	}
+	errs = multierr.Append(errs, checkWritable("crashLogFilename", config.CrashLogFilename))
+
+	if errs != nil {
+		return fmt.Errorf("invalid log config. %w", errs)
+	}
+
+	return nil
+}
+
+// validateLogger checks the settings which do not depend on the destinations.
+func (config *Config) validateLogger() error {
+	var errs error
+
+	if config.Level < DebugLevel || config.Level > FatalLevel {
+		errs = multierr.Append(errs, fmt.Errorf("invalid level %d", config.Level))
+	}
+	if _, err := parseLevelSpec(config.Levels); err != nil {
+		errs = multierr.Append(errs, fmt.Errorf("invalid levels. %w", err))
+	}
+	if config.TimestampFormat == "" {
+		errs = multierr.Append(errs, errors.New("timestampFormat must not be empty"))
+	}
+	if config.Sampling != nil {
+		errs = multierr.Append(errs, config.Sampling.validate())
+	}
+
+	return errs
+}
+
+// checkWritable makes sure file, when set, can be opened for appending without
+// creating it: an existing file is opened, otherwise the nearest existing
+// directory above it must accept new files.
+func checkWritable(key, file string) error {
+	if file == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(file); err == nil {
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			return fmt.Errorf("%s %s is not writable. %w", key, file, err)
+		}
+
+		return f.Close()
+	}
+
+	dir := filepath.Dir(file)
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s %s is not writable. %s is not a directory", key, file, dir)
+			}
+			break
+		}
+		if !os.IsNotExist(err) || filepath.Dir(dir) == dir {
+			return fmt.Errorf("%s %s is not writable. %w", key, file, err)
+		}
+		dir = filepath.Dir(dir)
+	}
+
+	// a probe file, removed right away, tells whether dir accepts new files
+	f, err := os.CreateTemp(dir, ".log-check-*")
+	if err != nil {
+		return fmt.Errorf("%s %s is not writable. %w", key, file, err)
+	}
+	_ = f.Close()
+
+	if err := os.Remove(f.Name()); err != nil {
+		return fmt.Errorf("%s %s is not writable. %w", key, file, err)
+	}
+
+	return nil
+}
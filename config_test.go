@@ -0,0 +1,148 @@
+package log
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	file := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func TestLoadConfig(t *testing.T) {
+	files := map[string]string{
+		"log.yaml": `
+level: warn
+enableColors: false
+errorLogFilename: /tmp/error.log
+name: main
+maxSize: 10
+maxBackups: 3
+fields:
+  app: demo
+`,
+		"log.json": `{
+	"level": "warn",
+	"enableColors": false,
+	"errorLogFilename": "/tmp/error.log",
+	"name": "main",
+	"maxSize": 10,
+	"maxBackups": 3,
+	"fields": {"app": "demo"}
+}`,
+		"log.toml": `
+level = "warn"
+enableColors = false
+errorLogFilename = "/tmp/error.log"
+name = "main"
+maxSize = 10
+maxBackups = 3
+
+[fields]
+app = "demo"
+`,
+	}
+
+	for name, content := range files {
+		config, err := LoadConfig(writeConfigFile(t, name, content))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if config.Level != WarnLevel || config.EnableColors || config.Name != "main" ||
+			config.ErrorLogFilename != "/tmp/error.log" || config.MaxSize != 10 || config.MaxBackups != 3 {
+			t.Errorf("%s: unexpected config %+v", name, config)
+		}
+		// untouched keys keep the NewLogConfig defaults
+		if config.Filename != "./logs/log.log" || config.TimestampFormat == "" {
+			t.Errorf("%s: defaults lost %+v", name, config)
+		}
+		if len(config.Fields) != 1 || config.Fields[0].Key != "app" || config.Fields[0].String != "demo" {
+			t.Errorf("%s: unexpected fields %+v", name, config.Fields)
+		}
+	}
+}
+
+func TestLoadConfigError(t *testing.T) {
+	tests := map[string]string{
+		"unknown.yaml":  "level: info\nlevle: debug\n",
+		"level.json":    `{"level": "verbose"}`,
+		"fields.toml":   `fields = "app"`,
+		"format.ini":    "level=info",
+		"broken.yaml":   "level: [",
+		"unknown.toml":  `filname = "a.log"`,
+		"unknown2.json": `{"maxSize": 1, "foo": 2}`,
+	}
+
+	for name, content := range tests {
+		if _, err := LoadConfig(writeConfigFile(t, name, content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		} else if !strings.Contains(err.Error(), name) && !strings.Contains(err.Error(), ".ini") {
+			t.Errorf("%s: error does not mention the file: %v", name, err)
+		}
+	}
+
+	if _, err := LoadConfig("/nonexistent/log.yaml"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	valid := NewLogConfig()
+	valid.Filename = filepath.Join(dir, "logs", "log.log")
+	valid.ErrorLogFilename = filepath.Join(dir, "logs", "error.log")
+	valid.CrashLogFilename = filepath.Join(dir, "crash.log")
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("default config should be valid: %v", err)
+	}
+	if entries, err := ioutil.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Errorf("Validate should not create anything, got %v %v", entries, err)
+	}
+
+	blocker := writeConfigFile(t, "blocker", "")
+
+	config := NewLogConfig()
+	config.Level = FatalLevel + 1
+	config.TimestampFormat = ""
+	config.MaxSize = -1
+	config.ErrorLogFilename = filepath.Join(blocker, "error.log")
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, s := range []string{"invalid level", "timestampFormat", "maxSize", "errorLogFilename"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("error does not report %s: %v", s, err)
+		}
+	}
+
+	prev := getLogger()
+	if err := config.Init(); err == nil {
+		t.Error("Init should fail")
+	}
+	if getLogger() != prev {
+		t.Error("failed Init should keep the global logger")
+	}
+	if _, err := config.New(); err == nil {
+		t.Error("New should fail")
+	}
+}
+
+func TestInitWithoutRotation(t *testing.T) {
+	keepGlobal(t)
+
+	config := &Config{Level: InfoLevel, TimestampFormat: "2006"}
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+	Info("without rotation settings")
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"context"
+	"sync"
+)
+
+type ctxKey struct{}
+
+// ContextExtractor pulls fields, as alternating keys and values or zap.Field,
+// out of a context, e.g. the request id set by a middleware.
+type ContextExtractor func(ctx context.Context) []interface{}
+
+var _extractors struct {
+	sync.RWMutex
+	fns []ContextExtractor
+}
+
+// RegisterContextExtractor adds an extractor applied by Ctx on every call.
+func RegisterContextExtractor(fn ContextExtractor) {
+	_extractors.Lock()
+	defer _extractors.Unlock()
+
+	_extractors.fns = append(_extractors.fns, fn)
+}
+
+// WithContext returns a copy of ctx carrying fields on top of those of ctx.
+// Only the fields are kept, FromContext and Ctx add them to the global logger
+// in place when they are called.
+func WithContext(ctx context.Context, fields ...interface{}) context.Context {
+	prev := contextFields(ctx)
+	return context.WithValue(ctx, ctxKey{}, append(prev[:len(prev):len(prev)], fields...))
+}
+
+// FromContext returns the global logger with the fields carried by ctx added.
+func FromContext(ctx context.Context) *Logger {
+	return withFields(getLogger(), contextFields(ctx))
+}
+
+// Ctx returns FromContext(ctx) with the fields of every registered
+// ContextExtractor added, e.g. log.Ctx(ctx).Infof("...").
+func Ctx(ctx context.Context) *Logger {
+	fields := contextFields(ctx)
+	if ctx == nil {
+		return withFields(getLogger(), fields)
+	}
+
+	_extractors.RLock()
+	defer _extractors.RUnlock()
+
+	fields = fields[:len(fields):len(fields)]
+	for _, fn := range _extractors.fns {
+		fields = append(fields, fn(ctx)...)
+	}
+
+	return withFields(getLogger(), fields)
+}
+
+func contextFields(ctx context.Context) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+
+	fields, _ := ctx.Value(ctxKey{}).([]interface{})
+	return fields
+}
+
+func withFields(l *Logger, fields []interface{}) *Logger {
+	if len(fields) == 0 {
+		return l
+	}
+
+	return l.With(fields...)
+}
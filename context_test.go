@@ -0,0 +1,79 @@
+package log
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+type requestIDKey struct{}
+
+func TestContext(t *testing.T) {
+	keepGlobal(t)
+
+	_extractors.Lock()
+	prevExtractors := _extractors.fns
+	_extractors.fns = nil
+	_extractors.Unlock()
+	defer func() {
+		_extractors.Lock()
+		_extractors.fns = prevExtractors
+		_extractors.Unlock()
+	}()
+
+	config, file := newTestConfig(t)
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if FromContext(context.Background()) != getLogger() {
+		t.Error("FromContext should fall back to the global logger")
+	}
+
+	RegisterContextExtractor(func(ctx context.Context) []interface{} {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return []interface{}{"request_id", id}
+		}
+		return nil
+	})
+
+	ctx := WithContext(context.Background(), "user_id", 7)
+	ctx = WithContext(ctx, "trace_id", "t1")
+	ctx = context.WithValue(ctx, requestIDKey{}, "r1")
+
+	var callers []string
+	callers = append(callers, callerLine(t))
+	FromContext(ctx).Infof("from context")
+	callers = append(callers, callerLine(t))
+	Ctx(ctx).Infof("ctx %s", "message")
+	callers = append(callers, callerLine(t))
+	Ctx(context.Background()).Infof("no fields")
+
+	// the context follows the global logger installed after its creation.
+	Fields("global", 1)
+	callers = append(callers, callerLine(t))
+	FromContext(ctx).Infof("after fields")
+	ResetFields()
+	child := WithContext(ctx, "child", true)
+	_ = WithContext(ctx, "sibling", true)
+	callers = append(callers, callerLine(t))
+	Ctx(child).Infof("child")
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, s := range []string{
+		callers[0] + "{user_id=7, trace_id=t1} - from context\n",
+		callers[1] + "{user_id=7, trace_id=t1, request_id=r1} - ctx message\n",
+		callers[2] + "- no fields\n",
+		callers[3] + "{global=1, user_id=7, trace_id=t1} - after fields\n",
+		callers[4] + "{user_id=7, trace_id=t1, child=true, request_id=r1} - child\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("missing %q in %q", s, out)
+		}
+	}
+}
@@ -1,19 +1,24 @@
+//go:build darwin
 // +build darwin
 
 package crash
 
 import (
-	"log"
+	"fmt"
 	"os"
 	"syscall"
 )
 
 // NewCrashLog set crash log
-func NewCrashLog(file string) {
+func NewCrashLog(file string) error {
 	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
-		log.Fatalf("open crash log file error. %v", err)
-	} else {
-		_ = syscall.Dup2(int(f.Fd()), 2)
+		return fmt.Errorf("open crash log file error. %w", err)
 	}
+
+	if err := syscall.Dup2(int(f.Fd()), 2); err != nil {
+		return fmt.Errorf("redirect stderr to crash log file error. %w", err)
+	}
+
+	return nil
 }
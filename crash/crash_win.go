@@ -1,9 +1,10 @@
+//go:build windows
 // +build windows
 
 package crash
 
 import (
-	"log"
+	"fmt"
 	"os"
 	"syscall"
 )
@@ -25,14 +26,16 @@ func setStdHandle(stdhandle int32, handle syscall.Handle) error {
 }
 
 // NewCrashLog set crash log
-func NewCrashLog(file string) {
+func NewCrashLog(file string) error {
 	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
-		log.Println(err.Error())
-	} else {
-		err = setStdHandle(syscall.STD_ERROR_HANDLE, syscall.Handle(f.Fd()))
-		if err != nil {
-			log.Fatalf("open crash log file error. %v", err)
-		}
+		return fmt.Errorf("open crash log file error. %w", err)
+	}
+
+	err = setStdHandle(syscall.STD_ERROR_HANDLE, syscall.Handle(f.Fd()))
+	if err != nil {
+		return fmt.Errorf("redirect stderr to crash log file error. %w", err)
 	}
+
+	return nil
 }
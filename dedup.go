@@ -0,0 +1,165 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// dedupCore writes entries like zapcore.NewCore, but collapses the entries
+// identical to the previous one within a window into a single "last message
+// repeated N times" entry, written when a different entry comes, the window
+// ends or the core is synced.
+type dedupCore struct {
+	zapcore.LevelEnabler
+	enc   zapcore.Encoder
+	state *dedupState
+}
+
+// dedupState is shared by the clones of a dedupCore, as they write to the same
+// output.
+type dedupState struct {
+	mu     sync.Mutex
+	window time.Duration
+	// enc has none of the fields added by With, for the repetitions entry.
+	enc zapcore.Encoder
+	out zapcore.WriteSyncer
+
+	// key is the encoded previous entry, without its time and stacktrace.
+	key   string
+	first time.Time
+	// last is the previous entry, count the number of its repetitions not
+	// written yet.
+	last  zapcore.Entry
+	count int
+	timer *time.Timer
+}
+
+func newDedupCore(enc zapcore.Encoder, out zapcore.WriteSyncer, enab zapcore.LevelEnabler, window time.Duration) zapcore.Core {
+	return &dedupCore{
+		LevelEnabler: enab,
+		enc:          enc,
+		state:        &dedupState{window: window, enc: enc.Clone(), out: out},
+	}
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for i := range fields {
+		fields[i].AddTo(enc)
+	}
+
+	return &dedupCore{LevelEnabler: c.LevelEnabler, enc: enc, state: c.state}
+}
+
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level > zapcore.ErrorLevel {
+		// the entries which may end the program are never held back.
+		s := c.state
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		err := s.flush()
+		s.key = ""
+		return multierr.Append(err, c.write(ent, fields))
+	}
+
+	keyEnt := ent
+	keyEnt.Time, keyEnt.Stack = time.Time{}, ""
+	buf, err := c.enc.EncodeEntry(keyEnt, fields)
+	if err != nil {
+		return err
+	}
+	key := buf.String()
+	buf.Free()
+
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key == s.key && ent.Time.Sub(s.first) < s.window {
+		s.last = ent
+		s.count++
+		if s.timer == nil {
+			s.timer = time.AfterFunc(s.first.Add(s.window).Sub(ent.Time), func() {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+
+				s.timer = nil
+				_ = s.flush()
+			})
+		}
+		return nil
+	}
+
+	err = s.flush()
+	s.key, s.first, s.last = key, ent.Time, ent
+
+	return multierr.Append(err, c.write(ent, fields))
+}
+
+// write writes ent to the output, it must be called with the state lock held.
+func (c *dedupCore) write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	_, err = c.state.out.Write(buf.Bytes())
+	buf.Free()
+
+	if ent.Level > zapcore.ErrorLevel {
+		// Since we may be crashing the program, sync the output.
+		_ = c.state.out.Sync()
+	}
+
+	return err
+}
+
+// flush writes the pending repetitions, it must be called with the state lock
+// held.
+func (s *dedupState) flush() error {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if s.count == 0 {
+		return nil
+	}
+
+	ent := zapcore.Entry{
+		Level:      s.last.Level,
+		Time:       s.last.Time,
+		LoggerName: s.last.LoggerName,
+		Message:    fmt.Sprintf("last message repeated %d times", s.count),
+	}
+	// the repetitions end the window, the next identical entry is written.
+	s.key, s.count = "", 0
+
+	buf, err := s.enc.EncodeEntry(ent, nil)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	_, err = s.out.Write(buf.Bytes())
+	return err
+}
+
+func (c *dedupCore) Sync() error {
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return multierr.Append(s.flush(), s.out.Sync())
+}
@@ -0,0 +1,199 @@
+package log
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedup(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.log")
+	plain := filepath.Join(dir, "plain.log")
+
+	config, err := LoadConfig(writeConfigFile(t, "log.yaml", `
+crashLogFilename: ""
+outputs:
+  - destination: `+file+`
+    dedup: 1h
+  - destination: `+plain+`
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := config.logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for i := 0; i < 4; i++ {
+		lines = append(lines, callerLine(t))
+		l.Infow("repeated", "k", "v")
+	}
+	lines = append(lines, callerLine(t))
+	l.Infow("repeated", "k", "other")
+	for i := 0; i < 3; i++ {
+		lines = append(lines, callerLine(t))
+		l.With("ctx", 1).Warn("context repeated")
+	}
+	lines = append(lines, callerLine(t))
+	l.Warn("context repeated")
+	lines = append(lines, callerLine(t))
+	l.Error("once")
+	for i := 0; i < 2; i++ {
+		lines = append(lines, callerLine(t))
+		l.Info("pending")
+	}
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if i := strings.Index(line, "]"); strings.HasPrefix(line, "[") && i > 0 {
+			got = append(got, line[i+1:])
+		}
+	}
+	want := []string{
+		"[INFO]" + lines[0] + "{k=v} - repeated",
+		"[INFO] - last message repeated 3 times",
+		"[INFO]" + lines[4] + "{k=other} - repeated",
+		"[WARN]" + lines[5] + "{ctx=1} - context repeated",
+		"[WARN] - last message repeated 2 times",
+		"[WARN]" + lines[8] + "- context repeated",
+		"[ERRO]" + lines[9] + "- once",
+		"[INFO]" + lines[10] + "- pending",
+		"[INFO] - last message repeated 1 times",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected output\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	data, err = ioutil.ReadFile(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), " - repeated\n"); n != 5 {
+		t.Errorf("output without dedup should keep every entry, got %d", n)
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	config, file := newTestConfig(t)
+	config.Outputs[0].Dedup = Duration(20 * time.Millisecond)
+	l, err := config.logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the entries share their caller.
+	repeated := func() { l.Info("repeated") }
+	repeated()
+	repeated()
+	repeated()
+
+	waitFor(t, func() bool {
+		data, err := ioutil.ReadFile(file)
+		return err == nil && strings.Contains(string(data), " - last message repeated 2 times\n")
+	})
+
+	repeated()
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), " - repeated\n"); n != 2 {
+		t.Errorf("expect the entry again after the window, got %d in %q", n, data)
+	}
+}
+
+func TestDedupPanic(t *testing.T) {
+	config, file := newTestConfig(t)
+	config.EnableErrorStacktrace = false
+	config.Outputs[0].Dedup = Duration(time.Hour)
+	l, err := config.logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		l.Info("repeated")
+	}
+	for i := 0; i < 2; i++ {
+		func() {
+			defer func() { _ = recover() }()
+			l.Panic("panic message")
+		}()
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	if n := strings.Count(out, " - panic message\n"); n != 2 {
+		t.Errorf("expect every panic entry, got %d in %q", n, out)
+	}
+	if i := strings.Index(out, "last message repeated 1 times"); i < 0 || i > strings.Index(out, "panic message") {
+		t.Errorf("the repetitions should be written before the panic entry: %q", out)
+	}
+}
+
+func TestDedupInit(t *testing.T) {
+	keepGlobal(t)
+
+	config, file := newTestConfig(t)
+	config.Outputs[0].Dedup = Duration(time.Hour)
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		Info("same")
+	}
+
+	config.Outputs[0].Destination = file + ".reloaded"
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if out := readFile(t, file); !strings.Contains(out, " - last message repeated 3 times\n") {
+		t.Errorf("Init should flush the previous cores: %q", out)
+	}
+}
+
+func TestDedupDefaultCores(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+
+	config := NewLogConfig()
+	config.EnableColors = false
+	config.Filename = file
+	config.ErrorLogFilename = ""
+	config.CrashLogFilename = ""
+	config.Dedup = Duration(time.Hour)
+	l, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		l.Info("same")
+	}
+	// syncing stdout fails when it is a pipe
+	_ = l.Sync()
+
+	out := readFile(t, file)
+	if strings.Count(out, " - same\n") != 1 || !strings.Contains(out, " - last message repeated 2 times\n") {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	config.Dedup = Duration(-time.Second)
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "invalid dedup") {
+		t.Errorf("expect a negative dedup error, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// elevation is a temporary level change shared by the loggers built from the
+// same Config.
+type elevation struct {
+	mu    sync.Mutex
+	level dynamicLevel
+	// base is the level to revert to and follow tells whether the level
+	// followed its parent before, both valid while timer is set.
+	base   zapcore.Level
+	follow bool
+	timer  *time.Timer
+	until  time.Time
+}
+
+// followingLevel is a level that follows the level of a parent until it is
+// set, like the level of a registered logger, see Get.
+type followingLevel interface {
+	dynamicLevel
+	following() bool
+	// follow drops the level set by SetLevel.
+	follow()
+	parentLevel() zapcore.Level
+}
+
+// ElevateLevel changes the level of l to level for d, after which the level in
+// effect before the elevation is restored. Elevating again while an elevation
+// is active changes its level and extends it to d from now. Both transitions
+// are logged.
+func (l *Logger) ElevateLevel(level zapcore.Level, d time.Duration) {
+	l.elevate(level, d)
+}
+
+// CancelElevation ends the active elevation of l right away, it reports
+// whether there was one.
+func (l *Logger) CancelElevation() bool {
+	return l.cancelElevation()
+}
+
+func (l *Logger) elevate(level zapcore.Level, d time.Duration) {
+	e := l.elevation
+	// attribute the transitions to the caller of ElevateLevel, past elevate
+	// and transition
+	log := l.skip.Desugar().WithOptions(zap.AddCallerSkip(2))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.timer == nil {
+		e.base = e.level.Level()
+		f, ok := e.level.(followingLevel)
+		e.follow = ok && f.following()
+	} else {
+		e.timer.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		// a stale timer, the elevation was extended or cancelled meanwhile
+		if e.timer != timer {
+			return
+		}
+		e.revert(log.WithOptions(zap.WithCaller(false)), "expired")
+	})
+	e.timer, e.until = timer, time.Now().Add(d)
+
+	from := e.level.Level()
+	transition(log, from, level, func() { e.level.SetLevel(level) },
+		"log level elevated from %s to %s until %s", from, level, e.until.Format(time.RFC3339))
+}
+
+func (l *Logger) cancelElevation() bool {
+	e := l.elevation
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.timer == nil {
+		return false
+	}
+
+	e.timer.Stop()
+	// attribute the transition to the caller of CancelElevation, past
+	// cancelElevation, revert and transition
+	e.revert(l.skip.Desugar().WithOptions(zap.AddCallerSkip(3)), "cancelled")
+	return true
+}
+
+// ElevatedUntil returns when the active elevation of l ends, ok is false when
+// there is none.
+func (l *Logger) ElevatedUntil() (until time.Time, ok bool) {
+	e := l.elevation
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.until, e.timer != nil
+}
+
+// stop forgets the active elevation without reverting the level.
+func (e *elevation) stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer, e.until = nil, time.Time{}
+	}
+}
+
+// revert must be called with e.mu held.
+func (e *elevation) revert(log *zap.Logger, reason string) {
+	from, to := e.level.Level(), e.base
+	set := func() { e.level.SetLevel(e.base) }
+	if f, ok := e.level.(followingLevel); ok && e.follow {
+		to = f.parentLevel()
+		set = func() { f.follow() }
+	}
+
+	transition(log, from, to, set, "log level elevation %s, reverting from %s to %s", reason, from, to)
+	e.timer, e.until = nil, time.Time{}
+}
+
+// transition changes the level from from to to by calling set, and logs the
+// change while the more verbose of both levels is active, as cycleLevel does.
+// The change is logged at info level, or at that level when it is above info.
+func transition(log *zap.Logger, from, to zapcore.Level, set func(), template string, args ...interface{}) {
+	lvl := from
+	if to < from {
+		set()
+		lvl = to
+	}
+
+	switch {
+	case lvl < zapcore.InfoLevel:
+		lvl = zapcore.InfoLevel
+	case lvl > zapcore.ErrorLevel:
+		// the levels above error panic or exit
+		lvl = zapcore.ErrorLevel
+	}
+	if ce := log.Check(lvl, fmt.Sprintf(template, args...)); ce != nil {
+		ce.Write()
+	}
+
+	if to >= from {
+		set()
+	}
+}
+
+// ElevateLevel temporarily changes the level of the global logger, see
+// Logger.ElevateLevel.
+func ElevateLevel(level zapcore.Level, d time.Duration) {
+	getLogger().elevate(level, d)
+}
+
+// CancelElevation ends the active elevation of the global logger.
+func CancelElevation() bool {
+	return getLogger().cancelElevation()
+}
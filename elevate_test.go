@@ -0,0 +1,104 @@
+package log
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestElevateLevel(t *testing.T) {
+	l, err := New(WithLevel(InfoLevel), WithFile(""), WithErrorFile(""), WithCrashFile(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.ElevateLevel(DebugLevel, 20*time.Millisecond)
+	if _, ok := l.ElevatedUntil(); !ok || l.Level() != DebugLevel {
+		t.Fatalf("expect elevated debug level, got %s", l.Level())
+	}
+	waitFor(t, func() bool { return l.Level() == InfoLevel })
+	if _, ok := l.ElevatedUntil(); ok {
+		t.Error("elevation should be over")
+	}
+
+	// extending outlives the first deadline
+	l.ElevateLevel(DebugLevel, 20*time.Millisecond)
+	l.Named("child").ElevateLevel(DebugLevel, time.Hour)
+	time.Sleep(50 * time.Millisecond)
+	if l.Level() != DebugLevel {
+		t.Errorf("extended elevation reverted early")
+	}
+
+	if !l.CancelElevation() || l.Level() != InfoLevel {
+		t.Errorf("cancel should revert to info, got %s", l.Level())
+	}
+	if l.CancelElevation() {
+		t.Error("nothing left to cancel")
+	}
+
+	// an explicit level wins over the elevation
+	l.ElevateLevel(DebugLevel, 20*time.Millisecond)
+	l.SetLevel(WarnLevel)
+	time.Sleep(50 * time.Millisecond)
+	if l.Level() != WarnLevel {
+		t.Errorf("SetLevel should end the elevation, got %s", l.Level())
+	}
+}
+
+func TestElevateLevelLogged(t *testing.T) {
+	keepGlobal(t)
+
+	config, file := newTestConfig(t)
+	config.Level = WarnLevel
+	config.EnableLineNumber = true
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	elevated := callerLine(t)
+	ElevateLevel(ErrorLevel, time.Hour)
+	cancelled := callerLine(t)
+	CancelElevation()
+
+	l := getLogger()
+	debug := callerLine(t)
+	l.ElevateLevel(DebugLevel, time.Hour)
+	reverted := callerLine(t)
+	l.CancelElevation()
+
+	want := []string{
+		"[WARN]" + elevated + "- log level elevated from warn to error until",
+		"[WARN]" + cancelled + "- log level elevation cancelled, reverting from error to warn\n",
+		"[INFO]" + debug + "- log level elevated from warn to debug until",
+		"[INFO]" + reverted + "- log level elevation cancelled, reverting from debug to warn\n",
+	}
+	out := readFile(t, file)
+	for _, s := range want {
+		if !strings.Contains(out, s) {
+			t.Errorf("missing %q in %q", s, out)
+		}
+	}
+}
+
+func TestLevelHandlerElevate(t *testing.T) {
+	l, err := New(WithLevel(InfoLevel), WithFile(""), WithErrorFile(""), WithCrashFile(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := l.LevelHandler()
+
+	code, body := serveLevel(t, h, http.MethodPut, `{"level":"debug","duration":"1h"}`)
+	if code != http.StatusOK || !strings.Contains(body, `"level":"debug"`) || !strings.Contains(body, `"elevatedUntil"`) {
+		t.Errorf("unexpected PUT response %d %s", code, body)
+	}
+
+	if code, _ := serveLevel(t, h, http.MethodPut, `{"duration":"1h"}`); code != http.StatusBadRequest {
+		t.Errorf("duration without level: expect 400, got %d", code)
+	}
+
+	code, body = serveLevel(t, h, http.MethodDelete, "")
+	if code != http.StatusOK || body != `{"level":"info","levels":""}` {
+		t.Errorf("unexpected DELETE response %d %s", code, body)
+	}
+}
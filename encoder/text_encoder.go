@@ -45,6 +45,8 @@ type textEncoder struct {
 	buf            *buffer.Buffer
 	spaced         bool // include spaces after colons and commas
 	openNamespaces int
+	// elems counts the elements of the innermost array being encoded.
+	elems int
 
 	// for encoding generic values by reflection
 	reflectBuf *buffer.Buffer
@@ -127,7 +129,7 @@ func (enc textEncoder) writeContext(line *buffer.Buffer, extra []zapcore.Field)
 	defer context.buf.Free()
 
 	addFields(context, extra)
-	// context.closeOpenNamespaces()
+	context.closeOpenNamespaces()
 	if context.buf.Len() == 0 {
 		return
 	}
@@ -139,6 +141,18 @@ func (enc textEncoder) writeContext(line *buffer.Buffer, extra []zapcore.Field)
 	// line.AppendByte('}')
 }
 
+// closeOpenNamespaces closes the namespaces opened by OpenNamespace, an empty
+// one is closed twice as no field has closed it.
+func (enc *textEncoder) closeOpenNamespaces() {
+	for i := 0; i < enc.openNamespaces; i++ {
+		if enc.endsWith('{') {
+			enc.buf.AppendByte('}')
+		}
+		enc.buf.AppendByte('}')
+	}
+	enc.openNamespaces = 0
+}
+
 func (enc textEncoder) addTabIfNecessary(line *buffer.Buffer) {
 	if line.Len() > 0 {
 		line.AppendByte('\t')
@@ -159,20 +173,21 @@ func (enc *textEncoder) resetReflectBuf() {
 
 func (enc *textEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
 	enc.addKey(key)
-
-	enc.addElementSeparator()
-	enc.buf.AppendByte('[')
-	// TODO:
-	// err := arr.MarshalLogArray(enc)
-	enc.buf.AppendByte(']')
-	// return err
-	return nil
+	elems := enc.elems
+	enc.elems = 0
+	err := enc.AppendArray(arr)
+	enc.elems = elems
+	enc.buf.AppendByte('}')
+	return err
 }
 func (enc *textEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
 	enc.addKey(key)
 	enc.addElementSeparator()
 	enc.buf.AppendByte('{')
 	err := obj.MarshalLogObject(enc)
+	if enc.endsWith('{') {
+		enc.buf.AppendByte('}')
+	}
 	enc.buf.AppendByte('}')
 	return err
 }
@@ -185,11 +200,13 @@ func (enc *textEncoder) AddByteString(key string, val []byte) {
 	enc.buf.AppendByte('"')
 	enc.safeAddByteString(val)
 	enc.buf.AppendByte('"')
+	enc.buf.AppendByte('}')
 }
 func (enc *textEncoder) AddBool(key string, val bool) {
 	enc.addKey(key)
 	enc.addElementSeparator()
 	enc.buf.AppendBool(val)
+	enc.buf.AppendByte('}')
 }
 
 //noinspection GoRedundantConversion
@@ -206,6 +223,7 @@ func (enc *textEncoder) AddComplex128(key string, val complex128) {
 	enc.buf.AppendFloat(i, 64)
 	enc.buf.AppendByte('i')
 	enc.buf.AppendByte('"')
+	enc.buf.AppendByte('}')
 }
 func (enc *textEncoder) AddDuration(key string, val time.Duration) {
 	enc.addKey(key)
@@ -218,6 +236,7 @@ func (enc *textEncoder) AddDuration(key string, val time.Duration) {
 		enc.addElementSeparator()
 		enc.buf.AppendInt(int64(val))
 	}
+	enc.buf.AppendByte('}')
 }
 func (enc *textEncoder) AddFloat64(key string, val float64) {
 	enc.addKey(key)
@@ -239,6 +258,7 @@ func (enc *textEncoder) AddReflected(key string, obj interface{}) error {
 	enc.reflectBuf.TrimNewline()
 	enc.addKey(key)
 	_, err = enc.buf.Write(enc.reflectBuf.Bytes())
+	enc.buf.AppendByte('}')
 	return err
 }
 func (enc *textEncoder) OpenNamespace(key string) {
@@ -265,6 +285,7 @@ func (enc *textEncoder) AddTime(key string, val time.Time) {
 		enc.addElementSeparator()
 		enc.buf.AppendInt(val.UnixNano())
 	}
+	enc.buf.AppendByte('}')
 }
 func (enc *textEncoder) AddUint64(key string, val uint64) {
 	enc.addKey(key)
@@ -286,6 +307,99 @@ func (enc *textEncoder) AddUint16(k string, v uint16)       { enc.AddUint64(k, u
 func (enc *textEncoder) AddUint8(k string, v uint8)         { enc.AddUint64(k, uint64(v)) }
 func (enc *textEncoder) AddUintptr(k string, v uintptr)     { enc.AddUint64(k, uint64(v)) }
 
+func (enc *textEncoder) AppendArray(arr zapcore.ArrayMarshaler) error {
+	enc.addArrayElementSeparator()
+	enc.buf.AppendByte('[')
+	elems := enc.elems
+	enc.elems = 0
+	err := arr.MarshalLogArray(enc)
+	enc.elems = elems
+	enc.buf.AppendByte(']')
+	return err
+}
+func (enc *textEncoder) AppendObject(obj zapcore.ObjectMarshaler) error {
+	enc.addArrayElementSeparator()
+	enc.buf.AppendByte('{')
+	err := obj.MarshalLogObject(enc)
+	if enc.endsWith('{') {
+		enc.buf.AppendByte('}')
+	}
+	return err
+}
+func (enc *textEncoder) AppendReflected(val interface{}) error {
+	enc.resetReflectBuf()
+	err := enc.reflectEnc.Encode(val)
+	if err != nil {
+		return err
+	}
+	enc.reflectBuf.TrimNewline()
+	enc.addArrayElementSeparator()
+	_, err = enc.buf.Write(enc.reflectBuf.Bytes())
+	return err
+}
+func (enc *textEncoder) AppendBool(val bool) {
+	enc.addArrayElementSeparator()
+	enc.buf.AppendBool(val)
+}
+func (enc *textEncoder) AppendByteString(val []byte) {
+	enc.addArrayElementSeparator()
+	enc.buf.AppendByte('"')
+	enc.safeAddByteString(val)
+	enc.buf.AppendByte('"')
+}
+
+//noinspection GoRedundantConversion
+func (enc *textEncoder) AppendComplex128(val complex128) {
+	enc.addArrayElementSeparator()
+	r, i := float64(real(val)), float64(imag(val))
+	enc.buf.AppendByte('"')
+	enc.buf.AppendFloat(r, 64)
+	enc.buf.AppendByte('+')
+	enc.buf.AppendFloat(i, 64)
+	enc.buf.AppendByte('i')
+	enc.buf.AppendByte('"')
+}
+func (enc *textEncoder) AppendDuration(val time.Duration) {
+	enc.addArrayElementSeparator()
+	enc.buf.AppendInt(int64(val))
+}
+func (enc *textEncoder) AppendFloat64(val float64) {
+	enc.addArrayElementSeparator()
+	enc.appendFloat(val, 64)
+}
+func (enc *textEncoder) AppendFloat32(val float32) {
+	enc.addArrayElementSeparator()
+	enc.appendFloat(float64(val), 32)
+}
+func (enc *textEncoder) AppendInt64(val int64) {
+	enc.addArrayElementSeparator()
+	enc.buf.AppendInt(val)
+}
+func (enc *textEncoder) AppendString(val string) {
+	enc.addArrayElementSeparator()
+	enc.safeAddString(val)
+}
+func (enc *textEncoder) AppendTime(val time.Time) {
+	enc.addArrayElementSeparator()
+	enc.buf.AppendInt(val.UnixNano())
+}
+func (enc *textEncoder) AppendUint64(val uint64) {
+	enc.addArrayElementSeparator()
+	enc.buf.AppendUint(val)
+}
+
+//noinspection GoRedundantConversion
+func (enc *textEncoder) AppendComplex64(v complex64) { enc.AppendComplex128(complex128(v)) }
+func (enc *textEncoder) AppendInt(v int)             { enc.AppendInt64(int64(v)) }
+func (enc *textEncoder) AppendInt32(v int32)         { enc.AppendInt64(int64(v)) }
+func (enc *textEncoder) AppendInt16(v int16)         { enc.AppendInt64(int64(v)) }
+func (enc *textEncoder) AppendInt8(v int8)           { enc.AppendInt64(int64(v)) }
+func (enc *textEncoder) AppendUint(v uint)           { enc.AppendUint64(uint64(v)) }
+func (enc *textEncoder) AppendUint32(v uint32)       { enc.AppendUint64(uint64(v)) }
+func (enc *textEncoder) AppendUint16(v uint16)       { enc.AppendUint64(uint64(v)) }
+func (enc *textEncoder) AppendUint8(v uint8)         { enc.AppendUint64(uint64(v)) }
+func (enc *textEncoder) AppendUintptr(v uintptr)     { enc.AppendUint64(uint64(v)) }
+
 func (enc *textEncoder) Clone() zapcore.Encoder {
 	clone := enc.clone()
 	_, _ = clone.buf.Write(enc.buf.Bytes())
@@ -308,7 +422,8 @@ func (enc *textEncoder) addKey(key string) {
 	if enc.replaceSeparator('}') {
 		enc.buf.AppendByte(',')
 		enc.buf.AppendByte(' ')
-	} else {
+	} else if !enc.endsWith('{') {
+		// the first field of an object or namespace is already opened.
 		enc.buf.AppendByte('{')
 	}
 	enc.safeAddString(key)
@@ -336,6 +451,20 @@ func (enc *textEncoder) replaceSeparator(v byte) bool {
 	return false
 }
 
+// addArrayElementSeparator separates the elements of an array with a comma.
+func (enc *textEncoder) addArrayElementSeparator() {
+	if enc.elems > 0 {
+		enc.buf.AppendByte(',')
+		enc.buf.AppendByte(' ')
+	}
+	enc.elems++
+}
+
+func (enc *textEncoder) endsWith(v byte) bool {
+	last := enc.buf.Len() - 1
+	return last >= 0 && enc.buf.Bytes()[last] == v
+}
+
 func (enc *textEncoder) addElementSeparator() {
 	last := enc.buf.Len() - 1
 	if last < 0 {
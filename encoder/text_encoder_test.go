@@ -0,0 +1,122 @@
+package encoder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type point struct{ x, y int }
+
+func (p point) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("x", p.x)
+	enc.AddInt("y", p.y)
+	return nil
+}
+
+type empty struct{}
+
+func (empty) MarshalLogObject(zapcore.ObjectEncoder) error { return nil }
+
+type matrix [][]int
+
+func (m matrix) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for i := range m {
+		row := m[i]
+		err := enc.AppendArray(zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+			for _, v := range row {
+				enc.AppendInt(v)
+			}
+			return nil
+		}))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeFields returns the context written by the text encoder for fields.
+func encodeFields(t *testing.T, fields ...zapcore.Field) string {
+	t.Helper()
+
+	enc := NewTextEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.Free()
+
+	return strings.TrimSpace(buf.String())
+}
+
+func TestTextEncoderArrays(t *testing.T) {
+	ts := time.Unix(0, 1000)
+	ptr := uintptr(0xff)
+
+	tests := []struct {
+		name  string
+		field zapcore.Field
+		want  string
+	}{
+		{"Bools", zap.Bools("k", []bool{true, false}), "{k=[true, false]}"},
+		{"ByteStrings", zap.ByteStrings("k", [][]byte{[]byte("a"), []byte("b")}), `{k=["a", "b"]}`},
+		{"Complex128s", zap.Complex128s("k", []complex128{1 + 2i}), `{k=["1+2i"]}`},
+		{"Complex64s", zap.Complex64s("k", []complex64{1 + 2i, 3}), `{k=["1+2i", "3+0i"]}`},
+		{"Durations", zap.Durations("k", []time.Duration{time.Second, 0}), "{k=[1000000000, 0]}"},
+		{"Float64s", zap.Float64s("k", []float64{1.5, -2}), "{k=[1.5, -2]}"},
+		{"Float32s", zap.Float32s("k", []float32{1.5}), "{k=[1.5]}"},
+		{"Ints", zap.Ints("k", []int{1, 2, 3}), "{k=[1, 2, 3]}"},
+		{"Int64s", zap.Int64s("k", []int64{-1}), "{k=[-1]}"},
+		{"Int32s", zap.Int32s("k", []int32{1, 2}), "{k=[1, 2]}"},
+		{"Int16s", zap.Int16s("k", []int16{1, 2}), "{k=[1, 2]}"},
+		{"Int8s", zap.Int8s("k", []int8{1, 2}), "{k=[1, 2]}"},
+		{"Strings", zap.Strings("k", []string{"a", "b c"}), "{k=[a, b c]}"},
+		{"Times", zap.Times("k", []time.Time{ts, ts}), "{k=[1000, 1000]}"},
+		{"Uints", zap.Uints("k", []uint{1, 2}), "{k=[1, 2]}"},
+		{"Uint64s", zap.Uint64s("k", []uint64{1}), "{k=[1]}"},
+		{"Uint32s", zap.Uint32s("k", []uint32{1, 2}), "{k=[1, 2]}"},
+		{"Uint16s", zap.Uint16s("k", []uint16{1, 2}), "{k=[1, 2]}"},
+		{"Uint8s", zap.Uint8s("k", []uint8{1, 2}), "{k=[1, 2]}"},
+		{"Uintptrs", zap.Uintptrs("k", []uintptr{ptr}), "{k=[255]}"},
+		{"Errors", zap.Errors("k", []error{errors.New("boom"), nil}), "{k=[{error=boom}]}"},
+		{"Empty", zap.Strings("k", nil), "{k=[]}"},
+		{"Objects", zap.Array("k", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+			_ = enc.AppendObject(point{1, 2})
+			return enc.AppendObject(empty{})
+		})), "{k=[{x=1, y=2}, {}]}"},
+		{"Nested", zap.Array("k", matrix{{1, 2}, {}, {3}}), "{k=[[1, 2], [], [3]]}"},
+		{"Reflected", zap.Array("k", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+			return enc.AppendReflected(map[string]int{"a": 1})
+		})), `{k=[{"a":1}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encodeFields(t, tt.field); got != tt.want {
+				t.Errorf("expect %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTextEncoderArraysAmongFields(t *testing.T) {
+	got := encodeFields(t,
+		zap.Ints("a", []int{1, 2}),
+		zap.Object("o", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("s", "v")
+			return enc.AddArray("b", matrix{{3}})
+		})),
+		zap.Strings("c", []string{"x"}),
+		zap.Int("n", 4),
+	)
+
+	want := "{a=[1, 2], o={s=v, b=[[3]]}, c=[x], n=4}"
+	if got != want {
+		t.Errorf("expect %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/multierr"
+)
+
+// ApplyEnv overlays environment variables on config. Every configuration key
+// maps to an upper snake case variable behind prefix, e.g. with prefix "LOG":
+// LOG_LEVEL, LOG_FILENAME, LOG_ENABLE_COLORS, LOG_MAX_SIZE and
+// LOG_ERROR_LOG_FILENAME. LOG_FIELDS takes comma separated key=value pairs.
+//
+// Values are applied in the order NewLogConfig() defaults, LoadConfig file,
+// ApplyEnv, RegisterFlags, so a variable that is set wins over the file and a
+// flag given on the command line wins over both. Variables that
+// are unset or empty leave the field untouched. Values that fail to parse are
+// all reported in the returned error and leave their field untouched.
+func (config *Config) ApplyEnv(prefix string) error {
+	var errs error
+	for _, v := range config.vars() {
+		name := envName(prefix, v.key)
+
+		s, ok := os.LookupEnv(name)
+		if !ok || s == "" {
+			continue
+		}
+
+		if err := v.value.Set(s); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("parse env %s=%q error. %w", name, s, err))
+		}
+	}
+
+	return errs
+}
+
+func envName(prefix, key string) string {
+	name := strings.ToUpper(strings.Join(splitKey(key), "_"))
+	if prefix == "" {
+		return name
+	}
+	if strings.HasSuffix(prefix, "_") {
+		return prefix + name
+	}
+
+	return prefix + "_" + name
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("LOG_FILENAME", "/tmp/app.log")
+	t.Setenv("LOG_ENABLE_COLORS", "false")
+	t.Setenv("LOG_MAX_SIZE", "5")
+	t.Setenv("LOG_ERROR_LOG_FILENAME", "")
+	t.Setenv("LOG_FIELDS", "app=demo, env=test")
+
+	config := NewLogConfig()
+	if err := config.ApplyEnv("LOG"); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Level != ErrorLevel || config.Filename != "/tmp/app.log" || config.EnableColors || config.MaxSize != 5 {
+		t.Errorf("env not applied: %+v", config)
+	}
+	if config.ErrorLogFilename != "./logs/error.log" {
+		t.Errorf("empty env should keep the default, got %q", config.ErrorLogFilename)
+	}
+	if len(config.Fields) != 2 || config.Fields[1].Key != "env" || config.Fields[1].String != "test" {
+		t.Errorf("unexpected fields %+v", config.Fields)
+	}
+}
+
+func TestApplyEnvError(t *testing.T) {
+	t.Setenv("APP_LOG_LEVEL", "verbose")
+	t.Setenv("APP_LOG_MAX_SIZE", "big")
+	t.Setenv("APP_LOG_COMPRESS", "true")
+
+	config := NewLogConfig()
+	err := config.ApplyEnv("APP_LOG_")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	for _, name := range []string{"APP_LOG_LEVEL", "APP_LOG_MAX_SIZE"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error does not report %s: %v", name, err)
+		}
+	}
+	if config.Level != DebugLevel || config.MaxSize != 200 || !config.Compress {
+		t.Errorf("unexpected config %+v", config)
+	}
+}
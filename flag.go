@@ -0,0 +1,35 @@
+package log
+
+import (
+	"flag"
+	"strings"
+)
+
+// RegisterFlags exposes every configuration key as a flag on fs (flag.CommandLine
+// when nil) named after prefix, e.g. with prefix "log": -log.level=warn,
+// -log.file=app.log, -log.error-file=error.log and -log.enable-colors=false.
+//
+// The flags write straight into config and their defaults are the values config
+// holds at registration time, so call it after LoadConfig and ApplyEnv to make
+// flags take precedence over both.
+func (config *Config) RegisterFlags(fs *flag.FlagSet, prefix string) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+
+	for _, v := range config.vars() {
+		fs.Var(v.value, flagName(prefix, v), v.usage)
+	}
+}
+
+func flagName(prefix string, v configVar) string {
+	name := v.flag
+	if name == "" {
+		name = strings.Join(splitKey(v.key), "-")
+	}
+	if prefix == "" || strings.HasSuffix(prefix, ".") || strings.HasSuffix(prefix, "-") {
+		return prefix + name
+	}
+
+	return prefix + "." + name
+}
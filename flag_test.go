@@ -0,0 +1,40 @@
+package log
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+)
+
+func TestRegisterFlags(t *testing.T) {
+	config := NewLogConfig()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	config.RegisterFlags(fs, "log")
+
+	if f := fs.Lookup("log.max-size"); f == nil || f.DefValue != "200" {
+		t.Fatalf("unexpected flag %+v", f)
+	}
+
+	err := fs.Parse([]string{
+		"-log.level=warn",
+		"-log.file=/tmp/app.log",
+		"-log.error-file=/tmp/error.log",
+		"-log.enable-colors=false",
+		"-log.compress",
+		"-log.max-backups=7",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Level != WarnLevel || config.Filename != "/tmp/app.log" || config.ErrorLogFilename != "/tmp/error.log" ||
+		config.EnableColors || !config.Compress || config.MaxBackups != 7 {
+		t.Errorf("flags not applied: %+v", config)
+	}
+
+	if err := fs.Parse([]string{"-log.level=verbose"}); err == nil {
+		t.Error("expected invalid level error")
+	}
+}
@@ -1,12 +1,18 @@
 module github.com/ehlxr/log
 
-go 1.13
+go 1.17
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/ehlxr/lumberjack v0.0.2-0.20200107093220-2a579f1b2e4d
 	github.com/robfig/cron/v3 v3.0.1
-	go.uber.org/multierr v1.6.0 // indirect
+	go.uber.org/multierr v1.6.0
 	go.uber.org/zap v1.16.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	go.uber.org/atomic v1.7.0 // indirect
 	golang.org/x/lint v0.0.0-20201208152925-83fdc39ff7b5 // indirect
 	golang.org/x/tools v0.0.0-20210104081019-d8d6ddbec6ee // indirect
 	honnef.co/go/tools v0.1.0 // indirect
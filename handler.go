@@ -0,0 +1,107 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelHandler returns an http.Handler for the level of the global logger, it
+// always acts on the logger installed at request time.
+//
+// GET responds with the current state, e.g. {"level":"info","levels":"db=warn"},
+// plus "elevatedUntil" while an elevation is active. PUT takes the same JSON
+// document, both keys are optional, and responds with the new state. A PUT
+// with a "duration" such as "15m" elevates the level for that long instead of
+// setting it, see ElevateLevel. DELETE cancels the active elevation.
+//
+// The responses also list the names of the loggers registered by Get under
+// "loggers".
+func LevelHandler() http.Handler {
+	return &levelHandler{logger: getLogger, names: Names}
+}
+
+// LevelHandler returns an http.Handler for the level of l, see the package
+// level LevelHandler.
+func (l *Logger) LevelHandler() http.Handler {
+	return &levelHandler{logger: func() *Logger { return l }}
+}
+
+type levelHandler struct {
+	logger func() *Logger
+	// names lists the registered loggers, nil for a Logger handler.
+	names func() []string
+}
+
+type levelState struct {
+	Level         zapcore.Level `json:"level"`
+	Levels        string        `json:"levels"`
+	ElevatedUntil string        `json:"elevatedUntil,omitempty"`
+	Loggers       []string      `json:"loggers,omitempty"`
+}
+
+type levelRequest struct {
+	Level    *zapcore.Level `json:"level"`
+	Levels   *string        `json:"levels"`
+	Duration string         `json:"duration"`
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l := h.logger()
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req levelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("request body must be well-formed JSON. %w", err))
+			return
+		}
+		var d time.Duration
+		if req.Duration != "" {
+			var err error
+			if d, err = time.ParseDuration(req.Duration); err != nil || d <= 0 || req.Level == nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("duration %q must be positive and come with a level", req.Duration))
+				return
+			}
+		}
+		if req.Levels != nil {
+			if err := l.SetLevels(*req.Levels); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		if d > 0 {
+			l.ElevateLevel(*req.Level, d)
+		} else if req.Level != nil {
+			l.SetLevel(*req.Level)
+		}
+	case http.MethodDelete:
+		l.CancelElevation()
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("only GET, PUT and DELETE are supported"))
+		return
+	}
+
+	state := levelState{Level: l.Level(), Levels: l.Levels()}
+	if until, ok := l.ElevatedUntil(); ok {
+		state.ElevatedUntil = until.Format(time.RFC3339)
+	}
+	if h.names != nil {
+		state.Loggers = h.names()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(state)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
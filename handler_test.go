@@ -0,0 +1,73 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveLevel(t *testing.T, h http.Handler, method, body string) (int, string) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, "/log/level", strings.NewReader(body)))
+
+	return w.Code, strings.TrimSpace(w.Body.String())
+}
+
+func TestLevelHandler(t *testing.T) {
+	keepGlobal(t)
+
+	config := NewDevelopmentConfig()
+	config.Level = InfoLevel
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	h := LevelHandler()
+	if code, body := serveLevel(t, h, http.MethodGet, ""); code != http.StatusOK || body != `{"level":"info","levels":""}` {
+		t.Errorf("unexpected GET response %d %s", code, body)
+	}
+
+	code, body := serveLevel(t, h, http.MethodPut, `{"level":"debug","levels":"db=error"}`)
+	if code != http.StatusOK || body != `{"level":"debug","levels":"db=error"}` {
+		t.Errorf("unexpected PUT response %d %s", code, body)
+	}
+	if GetLevel() != DebugLevel || GetLevels() != "db=error" {
+		t.Errorf("level not changed: %s %q", GetLevel(), GetLevels())
+	}
+
+	for _, body := range []string{`{"level":"verbose"}`, `{"levels":"db"}`, `level=info`} {
+		if code, _ := serveLevel(t, h, http.MethodPut, body); code != http.StatusBadRequest {
+			t.Errorf("PUT %s: expect 400, got %d", body, code)
+		}
+	}
+	if code, _ := serveLevel(t, h, http.MethodPost, ""); code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: expect 405, got %d", code)
+	}
+
+	SetLevel(WarnLevel)
+	if getLogger().Desugar().Core().Enabled(InfoLevel) {
+		t.Error("SetLevel should disable info")
+	}
+}
+
+func TestLoggerLevelHandler(t *testing.T) {
+	l, err := NewDevelopmentConfig().New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger, err := New(WithFile(""), WithErrorFile(""), WithCrashFile(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serveLevel(t, logger.LevelHandler(), http.MethodPut, `{"level":"error"}`)
+	if logger.Level() != ErrorLevel || logger.Named("child").Level() != ErrorLevel {
+		t.Errorf("unexpected level %s", logger.Level())
+	}
+	if !l.Desugar().Core().Enabled(DebugLevel) {
+		t.Error("other loggers should keep their level")
+	}
+}
@@ -0,0 +1,117 @@
+package log
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// DebugFn logs the message returned by fn, which is only called when
+// DebugLevel is enabled.
+func (l *Logger) DebugFn(fn func() string) {
+	if l.Enabled(zapcore.DebugLevel) {
+		l.skip.Debug(fn())
+	}
+}
+
+// DebugwFn logs the message and key-value pairs returned by fn, which is only
+// called when DebugLevel is enabled.
+func (l *Logger) DebugwFn(fn func() (string, []interface{})) {
+	if l.Enabled(zapcore.DebugLevel) {
+		msg, keysAndValues := fn()
+		l.skip.Debugw(msg, keysAndValues...)
+	}
+}
+
+func (l *Logger) InfoFn(fn func() string) {
+	if l.Enabled(zapcore.InfoLevel) {
+		l.skip.Info(fn())
+	}
+}
+
+func (l *Logger) InfowFn(fn func() (string, []interface{})) {
+	if l.Enabled(zapcore.InfoLevel) {
+		msg, keysAndValues := fn()
+		l.skip.Infow(msg, keysAndValues...)
+	}
+}
+
+func (l *Logger) WarnFn(fn func() string) {
+	if l.Enabled(zapcore.WarnLevel) {
+		l.skip.Warn(fn())
+	}
+}
+
+func (l *Logger) WarnwFn(fn func() (string, []interface{})) {
+	if l.Enabled(zapcore.WarnLevel) {
+		msg, keysAndValues := fn()
+		l.skip.Warnw(msg, keysAndValues...)
+	}
+}
+
+func (l *Logger) ErrorFn(fn func() string) {
+	if l.Enabled(zapcore.ErrorLevel) {
+		l.skip.Error(fn())
+	}
+}
+
+func (l *Logger) ErrorwFn(fn func() (string, []interface{})) {
+	if l.Enabled(zapcore.ErrorLevel) {
+		msg, keysAndValues := fn()
+		l.skip.Errorw(msg, keysAndValues...)
+	}
+}
+
+// DebugFn logs the message returned by fn with the global logger, fn is only
+// called when DebugLevel is enabled.
+func DebugFn(fn func() string) {
+	if l := getLogger(); l.Enabled(zapcore.DebugLevel) {
+		l.skip.Debug(fn())
+	}
+}
+
+// DebugwFn logs the message and key-value pairs returned by fn with the global
+// logger, fn is only called when DebugLevel is enabled.
+func DebugwFn(fn func() (string, []interface{})) {
+	if l := getLogger(); l.Enabled(zapcore.DebugLevel) {
+		msg, keysAndValues := fn()
+		l.skip.Debugw(msg, keysAndValues...)
+	}
+}
+
+func InfoFn(fn func() string) {
+	if l := getLogger(); l.Enabled(zapcore.InfoLevel) {
+		l.skip.Info(fn())
+	}
+}
+
+func InfowFn(fn func() (string, []interface{})) {
+	if l := getLogger(); l.Enabled(zapcore.InfoLevel) {
+		msg, keysAndValues := fn()
+		l.skip.Infow(msg, keysAndValues...)
+	}
+}
+
+func WarnFn(fn func() string) {
+	if l := getLogger(); l.Enabled(zapcore.WarnLevel) {
+		l.skip.Warn(fn())
+	}
+}
+
+func WarnwFn(fn func() (string, []interface{})) {
+	if l := getLogger(); l.Enabled(zapcore.WarnLevel) {
+		msg, keysAndValues := fn()
+		l.skip.Warnw(msg, keysAndValues...)
+	}
+}
+
+func ErrorFn(fn func() string) {
+	if l := getLogger(); l.Enabled(zapcore.ErrorLevel) {
+		l.skip.Error(fn())
+	}
+}
+
+func ErrorwFn(fn func() (string, []interface{})) {
+	if l := getLogger(); l.Enabled(zapcore.ErrorLevel) {
+		msg, keysAndValues := fn()
+		l.skip.Errorw(msg, keysAndValues...)
+	}
+}
@@ -0,0 +1,135 @@
+package log
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	keepGlobal(t)
+
+	config, file := newTestConfig(t)
+	config.Level = InfoLevel
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+	l := getLogger().Named("child")
+
+	if Enabled(DebugLevel) || l.Enabled(DebugLevel) || !Enabled(InfoLevel) || !l.Enabled(InfoLevel) {
+		t.Error("Enabled should follow the level")
+	}
+
+	calls := 0
+	msg := func() string { calls++; return "lazy message" }
+	msgw := func() (string, []interface{}) { calls++; return "lazyw message", []interface{}{"k", "v"} }
+
+	DebugFn(msg)
+	DebugwFn(msgw)
+	l.DebugFn(msg)
+	l.DebugwFn(msgw)
+	if calls != 0 {
+		t.Errorf("disabled fn called %d times", calls)
+	}
+
+	var want []string
+	want = append(want, callerLine(t))
+	InfoFn(msg)
+	want = append(want, callerLine(t))
+	WarnwFn(msgw)
+	want = append(want, callerLine(t))
+	l.ErrorFn(msg)
+	want = append(want, callerLine(t))
+	l.InfowFn(msgw)
+
+	SetLevel(DebugLevel)
+	if !Enabled(DebugLevel) || !l.Enabled(DebugLevel) {
+		t.Error("Enabled should follow SetLevel")
+	}
+	want = append(want, callerLine(t))
+	l.DebugFn(msg)
+
+	if calls != 5 {
+		t.Errorf("enabled fn called %d times, want 5", calls)
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for i, s := range []string{
+		"[INFO]" + want[0] + "- lazy message\n",
+		"[WARN]" + want[1] + "{k=v} - lazyw message\n",
+		"[ERRO]child" + want[2] + "- lazy message\n",
+		"[INFO]child" + want[3] + "{k=v} - lazyw message\n",
+		"[DEBU]child" + want[4] + "- lazy message\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("%d: missing %q in %q", i, s, out)
+		}
+	}
+}
+
+func TestLazyDisabledAllocs(t *testing.T) {
+	keepGlobal(t)
+
+	l := disabledLogger(t)
+	setLogger(l)
+
+	arg := 42
+	if n := testing.AllocsPerRun(100, func() {
+		_ = Enabled(DebugLevel)
+		DebugFn(func() string { return fmt.Sprint(arg) })
+		DebugwFn(func() (string, []interface{}) { return "msg", []interface{}{"arg", arg} })
+		l.DebugFn(func() string { return fmt.Sprint(arg) })
+		l.DebugwFn(func() (string, []interface{}) { return "msg", []interface{}{"arg", arg} })
+	}); n != 0 {
+		t.Errorf("disabled path allocates %v times", n)
+	}
+}
+
+func disabledLogger(tb testing.TB) *Logger {
+	config := NewLogConfig()
+	config.Level = InfoLevel
+	config.CrashLogFilename = ""
+	config.Outputs = []OutputConfig{{Destination: "stderr"}}
+
+	l, err := config.logger()
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return l
+}
+
+func BenchmarkDebugFnDisabled(b *testing.B) {
+	l := disabledLogger(b)
+	arg := 42
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.DebugFn(func() string { return fmt.Sprint(arg) })
+	}
+}
+
+func BenchmarkDebugwFnDisabled(b *testing.B) {
+	l := disabledLogger(b)
+	arg := 42
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.DebugwFn(func() (string, []interface{}) { return "msg", []interface{}{"arg", arg} })
+	}
+}
+
+func BenchmarkDebugfDisabled(b *testing.B) {
+	l := disabledLogger(b)
+	arg := 42
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debugf("%d", arg)
+	}
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// dynamicLevel is the level of a Logger, the zap.AtomicLevel of its Config or
+// the level of a logger registered by Get.
+type dynamicLevel interface {
+	zapcore.LevelEnabler
+	Level() zapcore.Level
+	SetLevel(zapcore.Level)
+}
+
+// SetLevel changes the level of l and of every logger derived from the same
+// Config, or only of a logger registered by Get and its children. It ends an
+// active elevation without reverting it.
+func (l *Logger) SetLevel(level zapcore.Level) {
+	l.elevation.stop()
+	l.level.SetLevel(level)
+}
+
+// Level returns the current level of l.
+func (l *Logger) Level() zapcore.Level {
+	return l.level.Level()
+}
+
+// Enabled reports whether l may write entries at level. It is cheap enough to
+// guard the building of expensive arguments, a logger name level override may
+// still drop the entry.
+func (l *Logger) Enabled(level zapcore.Level) bool {
+	return l.Desugar().Core().Enabled(level)
+}
+
+// SetLevels replaces the logger name level overrides shared by l and every
+// logger derived from the same Config, see Config.Levels for the spec format.
+func (l *Logger) SetLevels(spec string) error {
+	return l.nameLevels().set(spec)
+}
+
+// Levels returns the current logger name level overrides spec.
+func (l *Logger) Levels() string {
+	return l.nameLevels().get().spec
+}
+
+func (l *Logger) nameLevels() *nameLevels {
+	if l.levels == nil {
+		return _logger.Load().(*globalLogger).base.levels
+	}
+
+	return l.levels
+}
+
+// SetLevel changes the level of the global logger.
+func SetLevel(level zapcore.Level) {
+	getLogger().SetLevel(level)
+}
+
+// GetLevel returns the current level of the global logger.
+func GetLevel() zapcore.Level {
+	return getLogger().Level()
+}
+
+// Enabled reports whether the global logger may write entries at level, see
+// Logger.Enabled.
+func Enabled(level zapcore.Level) bool {
+	return getLogger().Enabled(level)
+}
+
+// SetLevels replaces the logger name level overrides of the global logger.
+func SetLevels(spec string) error {
+	return getLogger().SetLevels(spec)
+}
+
+// GetLevels returns the logger name level overrides of the global logger.
+func GetLevels() string {
+	return getLogger().Levels()
+}
@@ -17,8 +17,6 @@ var (
 		zapcore.FatalLevel:  Red,
 	}
 	_unknownLevelColor = Red
-
-	_levelToColorStrings = make(map[zapcore.Level]string, len(_levelToColor))
 )
 
 type Color uint8
@@ -35,7 +33,11 @@ const (
 	White
 )
 
-func (config *logConfig) initColor() {
+// colorLevelEncoder returns a level encoder using the colors of _levelToColor,
+// each built logger gets its own strings so that building a logger never races
+// with the ones in use.
+func (config *Config) colorLevelEncoder() zapcore.LevelEncoder {
+	levelToColorStrings := make(map[zapcore.Level]string, len(_levelToColor))
 	for level, color := range _levelToColor {
 		lcs := level.String()
 
@@ -46,7 +48,16 @@ func (config *logConfig) initColor() {
 		if config.EnableLevelTruncation {
 			lcs = lcs[:4]
 		}
-		_levelToColorStrings[level] = color.Add(lcs)
+		levelToColorStrings[level] = color.Add(lcs)
+	}
+
+	return func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		s, ok := levelToColorStrings[l]
+		if !ok {
+			s = _unknownLevelColor.Add(l.CapitalString())
+		}
+
+		enc.AppendString(fmt.Sprintf("[%s]", s))
 	}
 }
 
@@ -0,0 +1,189 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// _allLevels is the level of the cores wrapped by nameLevelCore, which checks
+// the level once for all of them.
+var _allLevels = zap.LevelEnablerFunc(func(zapcore.Level) bool { return true })
+
+// nameLevels holds the logger name level overrides of a Config. It is shared by
+// every logger built from the Config so that the overrides can be replaced at
+// runtime.
+type nameLevels struct {
+	v atomic.Value // *levelSpec
+}
+
+func newNameLevels(spec string) *nameLevels {
+	ls := &nameLevels{}
+	if err := ls.set(spec); err != nil {
+		// Validate has already rejected a malformed spec.
+		ls.v.Store(&levelSpec{})
+	}
+
+	return ls
+}
+
+func (ls *nameLevels) get() *levelSpec {
+	return ls.v.Load().(*levelSpec)
+}
+
+func (ls *nameLevels) set(spec string) error {
+	s, err := parseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	ls.v.Store(s)
+	return nil
+}
+
+type levelRule struct {
+	name  string
+	level zapcore.Level
+}
+
+type levelSpec struct {
+	spec  string
+	rules []levelRule
+	// min is the lowest level of all rules.
+	min zapcore.Level
+
+	// cache maps a logger name to its resolved rule, names are few and
+	// long-lived so it is never evicted.
+	cache sync.Map
+}
+
+func parseLevelSpec(spec string) (*levelSpec, error) {
+	s := &levelSpec{spec: spec, min: zapcore.FatalLevel + 1}
+
+	for _, pair := range strings.Split(spec, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		name := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || name == "" {
+			return nil, fmt.Errorf("invalid level spec %q, expect name=level", pair)
+		}
+
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(kv[1]))); err != nil {
+			return nil, fmt.Errorf("invalid level spec %q. %w", pair, err)
+		}
+
+		s.rules = append(s.rules, levelRule{name: name, level: level})
+		if level < s.min {
+			s.min = level
+		}
+	}
+
+	return s, nil
+}
+
+// level returns the level of the rule matching the deepest segment of the
+// logger name, the longest one among them, ok is false when no rule applies.
+func (s *levelSpec) level(loggerName string) (level zapcore.Level, ok bool) {
+	if len(s.rules) == 0 {
+		return level, false
+	}
+
+	if r, found := s.cache.Load(loggerName); found {
+		if r == nil {
+			return level, false
+		}
+		return r.(levelRule).level, true
+	}
+
+	name := strings.NewReplacer("[", "", "]", "").Replace(loggerName)
+
+	var (
+		best    *levelRule
+		bestEnd int
+	)
+	for i := range s.rules {
+		r := &s.rules[i]
+		end := matchEnd(name, r.name)
+		if end < 0 {
+			continue
+		}
+		if best == nil || end > bestEnd || (end == bestEnd && len(r.name) >= len(best.name)) {
+			best, bestEnd = r, end
+		}
+	}
+
+	if best == nil {
+		s.cache.Store(loggerName, nil)
+		return level, false
+	}
+
+	s.cache.Store(loggerName, *best)
+	return best.level, true
+}
+
+// matchEnd returns the end of the rightmost run of whole segments of the dot
+// separated logger name equal to rule, -1 when there is none. "*" matches
+// every name, before its first segment.
+func matchEnd(name, rule string) int {
+	if rule == "*" {
+		return 0
+	}
+
+	for end := len(name); end > 0; {
+		i := strings.LastIndex(name[:end], rule)
+		if i < 0 {
+			break
+		}
+		end = i + len(rule)
+		if (i == 0 || name[i-1] == '.') && (end == len(name) || name[end] == '.') {
+			return end
+		}
+		// look for an occurrence starting before i, possibly overlapping.
+		end--
+	}
+
+	return -1
+}
+
+// nameLevelCore checks the level of an entry against the logger name level
+// overrides, falling back to the atomic level of the Config, or the level of a
+// registered logger, see Get.
+type nameLevelCore struct {
+	zapcore.Core
+	level  zapcore.LevelEnabler
+	levels *nameLevels
+}
+
+func newNameLevelCore(core zapcore.Core, level zap.AtomicLevel, levels *nameLevels) zapcore.Core {
+	return &nameLevelCore{Core: core, level: level, levels: levels}
+}
+
+// Enabled is checked before the logger name is known, it only rules out the
+// levels that neither the atomic level nor any override enables.
+func (c *nameLevelCore) Enabled(lvl zapcore.Level) bool {
+	return c.level.Enabled(lvl) || lvl >= c.levels.get().min
+}
+
+func (c *nameLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &nameLevelCore{Core: c.Core.With(fields), level: c.level, levels: c.levels}
+}
+
+func (c *nameLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if level, ok := c.levels.get().level(ent.LoggerName); ok {
+		if ent.Level < level {
+			return ce
+		}
+	} else if !c.level.Enabled(ent.Level) {
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+}
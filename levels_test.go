@@ -0,0 +1,136 @@
+package log
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLevels(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(
+		WithLevel(InfoLevel),
+		WithLevels("db=warn, http=debug"),
+		WithFile(file),
+		WithErrorFile(""),
+		WithCrashFile(""),
+		WithColors(false),
+		WithName("svc"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Named("db").Info("db info")
+	l.Named("db").Named("query").Warn("db query warn")
+	l.Named("http").Debug("http debug")
+	l.Named("httpx").Debug("httpx debug")
+	l.Named("other").Debug("other debug")
+	l.Info("svc info")
+
+	if err := l.SetLevels("*=error"); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("svc info after")
+	l.Named("http").Error("http error after")
+
+	if err := l.SetLevels("db"); err == nil {
+		t.Error("expected invalid spec error")
+	}
+	if l.Levels() != "*=error" {
+		t.Errorf("unexpected levels %q", l.Levels())
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, s := range []string{"db query warn", "http debug", "svc info", "http error after"} {
+		if !strings.Contains(out, s+"\n") {
+			t.Errorf("missing %q in %q", s, out)
+		}
+	}
+	for _, s := range []string{"db info", "httpx debug", "other debug", "svc info after"} {
+		if strings.Contains(out, s+"\n") {
+			t.Errorf("unexpected %q in %q", s, out)
+		}
+	}
+}
+
+func TestLevelsDeepestRule(t *testing.T) {
+	tests := []struct {
+		spec, name string
+		want       zapcore.Level
+	}{
+		{"service=info,db=debug", "[service].db", DebugLevel},
+		{"db=debug,service=info", "[service].db", DebugLevel},
+		{"main=warn,db=debug", "[main].db", DebugLevel},
+		{"main=warn,db=debug", "[main]", WarnLevel},
+		{"db=warn,query=debug", "[main].db.query", DebugLevel},
+		{"db=warn,main.db=error", "[main].db", ErrorLevel},
+		{"db=warn,*=debug", "[main].db.query", WarnLevel},
+		{"db=warn,*=debug", "[main].http", DebugLevel},
+	}
+
+	for _, tt := range tests {
+		s, err := parseLevelSpec(tt.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := s.level(tt.name); !ok || got != tt.want {
+			t.Errorf("level %q of %q = %s, want %s", tt.spec, tt.name, got, tt.want)
+		}
+	}
+
+	file := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(
+		WithLevel(InfoLevel),
+		WithLevels("svc=info,db=debug"),
+		WithFile(file),
+		WithErrorFile(""),
+		WithCrashFile(""),
+		WithName("svc"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("svc debug")
+	l.Named("db").Debug("db debug")
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := string(data); strings.Contains(out, "svc debug") || !strings.Contains(out, "db debug") {
+		t.Errorf("db should be raised to debug under svc: %q", out)
+	}
+}
+
+func TestMatchEnd(t *testing.T) {
+	tests := []struct {
+		name, rule string
+		want       int
+	}{
+		{"db", "db", 2},
+		{"main.db", "db", 7},
+		{"main.db.query", "db", 7},
+		{"db.query", "db", 2},
+		{"main.db.query", "db.query", 13},
+		{"db.x.db", "db", 7},
+		{"a.aa.a", "a.a", -1},
+		{"a.a.a", "a.a", 5},
+		{"main.dbx", "db", -1},
+		{"main", "*", 0},
+		{"", "*", 0},
+	}
+
+	for _, tt := range tests {
+		if got := matchEnd(tt.name, tt.rule); got != tt.want {
+			t.Errorf("matchEnd(%q, %q) = %d, want %d", tt.name, tt.rule, got, tt.want)
+		}
+	}
+}
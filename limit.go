@@ -0,0 +1,212 @@
+package log
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// _sites maps the call site of Every, FirstN and EveryN to its *site.
+var _sites = struct {
+	sync.RWMutex
+	m map[uintptr]*site
+}{m: make(map[uintptr]*site)}
+
+// site counts the entries of one call site, allowed or suppressed.
+type site struct {
+	mu         sync.Mutex
+	allow      func() bool
+	suppressed int
+}
+
+// Limited logs through a Logger only the entries allowed at its call site,
+// the first entry written after some were suppressed carries their number in
+// a "suppressed" field. Entries of a disabled level are not counted.
+type Limited struct {
+	l    *Logger
+	site *site
+}
+
+// Every limits the call site to one entry per d.
+func (l *Logger) Every(d time.Duration) Limited {
+	return limit(l, every, int64(d))
+}
+
+// FirstN limits the call site to its first n entries.
+func (l *Logger) FirstN(n int) Limited {
+	return limit(l, firstN, int64(n))
+}
+
+// EveryN limits the call site to one entry out of n, starting with the first.
+func (l *Logger) EveryN(n int) Limited {
+	return limit(l, everyN, int64(n))
+}
+
+// Every limits the call site to one entry per d of the global logger.
+func Every(d time.Duration) Limited {
+	return limit(getLogger(), every, int64(d))
+}
+
+// FirstN limits the call site to its first n entries of the global logger.
+func FirstN(n int) Limited {
+	return limit(getLogger(), firstN, int64(n))
+}
+
+// EveryN limits the call site to one entry out of n of the global logger.
+func EveryN(n int) Limited {
+	return limit(getLogger(), everyN, int64(n))
+}
+
+func every(n int64) func() bool {
+	d := time.Duration(n)
+	var last time.Time
+	return func() bool {
+		now := time.Now()
+		if !last.IsZero() && now.Sub(last) < d {
+			return false
+		}
+		last = now
+		return true
+	}
+}
+
+func firstN(n int64) func() bool {
+	var count int64
+	return func() bool {
+		count++
+		return count <= n
+	}
+}
+
+func everyN(n int64) func() bool {
+	var count int64
+	return func() bool {
+		count++
+		return n <= 1 || count%n == 1
+	}
+}
+
+// limit returns the Limited of the call site of the function calling it. The
+// policy of a call site seen for the first time is built by policy from n, the
+// known call sites are looked up without allocating.
+func limit(l *Logger, policy func(n int64) func() bool, n int64) Limited {
+	var pcs [1]uintptr
+	// skip runtime.Callers, limit and Every, FirstN or EveryN.
+	runtime.Callers(3, pcs[:])
+
+	_sites.RLock()
+	s, ok := _sites.m[pcs[0]]
+	_sites.RUnlock()
+	if ok {
+		return Limited{l: l, site: s}
+	}
+
+	_sites.Lock()
+	defer _sites.Unlock()
+
+	if s, ok = _sites.m[pcs[0]]; !ok {
+		s = &site{allow: policy(n)}
+		_sites.m[pcs[0]] = s
+	}
+
+	return Limited{l: l, site: s}
+}
+
+// logger returns the logger to write an entry at level with, nil when the
+// entry is disabled or suppressed.
+func (r Limited) logger(level zapcore.Level) *zap.SugaredLogger {
+	if !r.l.Enabled(level) {
+		return nil
+	}
+
+	s := r.site
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.allow() {
+		s.suppressed++
+		return nil
+	}
+
+	if n := s.suppressed; n > 0 {
+		s.suppressed = 0
+		return r.l.skip.With("suppressed", n)
+	}
+
+	return r.l.skip
+}
+
+func (r Limited) Debug(args ...interface{}) {
+	if l := r.logger(zapcore.DebugLevel); l != nil {
+		l.Debug(args...)
+	}
+}
+
+func (r Limited) Debugf(template string, args ...interface{}) {
+	if l := r.logger(zapcore.DebugLevel); l != nil {
+		l.Debugf(template, args...)
+	}
+}
+
+func (r Limited) Debugw(msg string, keysAndValues ...interface{}) {
+	if l := r.logger(zapcore.DebugLevel); l != nil {
+		l.Debugw(msg, keysAndValues...)
+	}
+}
+
+func (r Limited) Info(args ...interface{}) {
+	if l := r.logger(zapcore.InfoLevel); l != nil {
+		l.Info(args...)
+	}
+}
+
+func (r Limited) Infof(template string, args ...interface{}) {
+	if l := r.logger(zapcore.InfoLevel); l != nil {
+		l.Infof(template, args...)
+	}
+}
+
+func (r Limited) Infow(msg string, keysAndValues ...interface{}) {
+	if l := r.logger(zapcore.InfoLevel); l != nil {
+		l.Infow(msg, keysAndValues...)
+	}
+}
+
+func (r Limited) Warn(args ...interface{}) {
+	if l := r.logger(zapcore.WarnLevel); l != nil {
+		l.Warn(args...)
+	}
+}
+
+func (r Limited) Warnf(template string, args ...interface{}) {
+	if l := r.logger(zapcore.WarnLevel); l != nil {
+		l.Warnf(template, args...)
+	}
+}
+
+func (r Limited) Warnw(msg string, keysAndValues ...interface{}) {
+	if l := r.logger(zapcore.WarnLevel); l != nil {
+		l.Warnw(msg, keysAndValues...)
+	}
+}
+
+func (r Limited) Error(args ...interface{}) {
+	if l := r.logger(zapcore.ErrorLevel); l != nil {
+		l.Error(args...)
+	}
+}
+
+func (r Limited) Errorf(template string, args ...interface{}) {
+	if l := r.logger(zapcore.ErrorLevel); l != nil {
+		l.Errorf(template, args...)
+	}
+}
+
+func (r Limited) Errorw(msg string, keysAndValues ...interface{}) {
+	if l := r.logger(zapcore.ErrorLevel); l != nil {
+		l.Errorw(msg, keysAndValues...)
+	}
+}
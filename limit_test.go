@@ -0,0 +1,92 @@
+package log
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resetSites forgets the call sites limited so far, e.g. by a previous run of
+// the test.
+func resetSites() {
+	_sites.Lock()
+	_sites.m = make(map[uintptr]*site)
+	_sites.Unlock()
+}
+
+func TestLimited(t *testing.T) {
+	keepGlobal(t)
+	resetSites()
+
+	config, file := newTestConfig(t)
+	config.Level = InfoLevel
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+	l := getLogger().Named("child")
+
+	var line string
+	for i := 0; i < 10; i++ {
+		FirstN(3).Infof("first %d", i)
+		EveryN(4).Warnw("every n", "i", i)
+		l.EveryN(5).Info("child every n ", i)
+		EveryN(2).Debugf("disabled %d", i)
+		line = callerLine(t)
+		Every(time.Hour).Errorf("every %d", i)
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, s := range []string{
+		" - first 0\n", " - first 1\n", " - first 2\n",
+		"{i=0} - every n\n", "{suppressed=3, i=4} - every n\n", "{suppressed=3, i=8} - every n\n",
+		"[INFO]child", " - child every n 0\n", "{suppressed=4} - child every n 5\n",
+		"[ERRO]" + line + "- every 0\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("missing %q in %q", s, out)
+		}
+	}
+	for _, s := range []string{"first 3", "disabled", "every 1\n", "{i=1}"} {
+		if strings.Contains(out, s) {
+			t.Errorf("%q should be suppressed in %q", s, out)
+		}
+	}
+}
+
+func TestLimitedSites(t *testing.T) {
+	l, err := NewDevelopmentConfig().logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := l.FirstN(1), l.FirstN(1)
+	if a.site == b.site {
+		t.Error("call sites should be limited separately")
+	}
+
+	sites := make(map[*site]bool)
+	for i := 0; i < 3; i++ {
+		sites[l.EveryN(2).site] = true
+	}
+	if len(sites) != 1 {
+		t.Errorf("a call site should share its limit, got %d", len(sites))
+	}
+}
+
+func TestLimitedKnownSiteAllocs(t *testing.T) {
+	l := disabledLogger(t)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Every(time.Hour).Debug()
+		l.FirstN(1).Debug()
+		l.EveryN(2).Debug()
+	})
+	if allocs != 0 {
+		t.Errorf("a known call site should not allocate, got %v allocs", allocs)
+	}
+}
@@ -3,10 +3,11 @@ package log
 import (
 	"fmt"
 	"github.com/ehlxr/lumberjack"
-	"log"
 	"os"
 	"path"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ehlxr/log/bufferpool"
@@ -17,7 +18,9 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-var logger *zap.SugaredLogger
+// _logger holds the global *Logger, it is swapped atomically so that
+// Init and configuration reloads never race with concurrent logging.
+var _logger atomic.Value
 
 const (
 	DebugLevel  = zapcore.DebugLevel
@@ -29,46 +32,89 @@ const (
 	FatalLevel  = zapcore.FatalLevel
 )
 
-type logConfig struct {
-	Level            zapcore.Level
-	EnableColors     bool
-	CrashLogFilename string
-	ErrorLogFilename string
-	EnableLineNumber bool
-	AddCallerSkip    int
+// Config describes how a logger is built, start from NewLogConfig, LoadConfig
+// or the options accepted by New.
+type Config struct {
+	Level            zapcore.Level `json:"level" yaml:"level" toml:"level"`
+	EnableColors     bool          `json:"enableColors" yaml:"enableColors" toml:"enableColors"`
+	CrashLogFilename string        `json:"crashLogFilename" yaml:"crashLogFilename" toml:"crashLogFilename"`
+	ErrorLogFilename string        `json:"errorLogFilename" yaml:"errorLogFilename" toml:"errorLogFilename"`
+	EnableLineNumber bool          `json:"enableLineNumber" yaml:"enableLineNumber" toml:"enableLineNumber"`
+	AddCallerSkip    int           `json:"addCallerSkip" yaml:"addCallerSkip" toml:"addCallerSkip"`
 
 	// enable the truncation of the level text to 4 characters.
-	EnableLevelTruncation bool
-	EnableErrorStacktrace bool
-	TimestampFormat       string
-	EnableCapitalLevel    bool
+	EnableLevelTruncation bool   `json:"enableLevelTruncation" yaml:"enableLevelTruncation" toml:"enableLevelTruncation"`
+	EnableErrorStacktrace bool   `json:"enableErrorStacktrace" yaml:"enableErrorStacktrace" toml:"enableErrorStacktrace"`
+	TimestampFormat       string `json:"timestampFormat" yaml:"timestampFormat" toml:"timestampFormat"`
+	EnableCapitalLevel    bool   `json:"enableCapitalLevel" yaml:"enableCapitalLevel" toml:"enableCapitalLevel"`
 	atomicLevel           zap.AtomicLevel
-	Name                  string
-	Fields                []zap.Field
-
-	*lumberjack.Logger
+	levels                *nameLevels
+	sampling              *samplingCounters
+	writers               []*fileWriter
+	Name                  string      `json:"name" yaml:"name" toml:"name"`
+	Fields                []zap.Field `json:"-" yaml:"-" toml:"-"`
+
+	// Levels overrides Level by logger name, e.g. "db=warn,http=debug,*=info".
+	// A rule applies to the loggers whose dot separated name contains it, the
+	// rule matching the deepest segment wins, e.g. db=debug over main=warn for
+	// "[main].db", then the longest one. "*" matches every logger.
+	Levels string `json:"levels,omitempty" yaml:"levels,omitempty" toml:"levels,omitempty"`
+
+	// annotate entries with the full path of the caller instead of file:line.
+	EnableFullCaller bool `json:"enableFullCaller" yaml:"enableFullCaller" toml:"enableFullCaller"`
+	// put the logger in development mode, DPanic entries panic.
+	Development bool `json:"development" yaml:"development" toml:"development"`
+
+	// Sampling caps the throughput of the logger, nil disables sampling.
+	Sampling *SamplingConfig `json:"sampling,omitempty" yaml:"sampling,omitempty" toml:"sampling,omitempty"`
+
+	// Dedup collapses the repeated entries written to the default stdout,
+	// Filename and ErrorLogFilename destinations, see OutputConfig.Dedup for
+	// the destinations of Outputs.
+	Dedup Duration `json:"dedup,omitempty" yaml:"dedup,omitempty" toml:"dedup,omitempty"`
+
+	// Outputs replaces the default stdout, Filename and ErrorLogFilename
+	// destinations when not empty.
+	Outputs []OutputConfig `json:"outputs,omitempty" yaml:"outputs,omitempty" toml:"outputs,omitempty"`
+
+	*lumberjack.Logger `yaml:",inline"`
 }
 
 func init() {
-	config := &logConfig{
+	config := &Config{
+		TimestampFormat: time.RFC3339,
 		Logger: &lumberjack.Logger{
 			LocalTime: true,
 		},
 	}
 
-	logger = config.newLogger().Sugar()
+	l, err := config.logger()
+	if err != nil {
+		panic(err)
+	}
+
+	setLogger(l)
 }
 
-func (config *logConfig) Init() {
-	logger = config.newLogger().Sugar()
+// Init validates config and replaces the global logger with one built from it.
+// On error the global logger is left untouched.
+func (config *Config) Init() error {
+	l, err := config.logger()
+	if err != nil {
+		return err
+	}
+
+	setLogger(l)
+	return nil
 }
 
-func (config *logConfig) New() *zap.SugaredLogger {
-	return config.newLogger().Sugar()
+// New validates config and builds a new logger from it.
+func (config *Config) New() (*Logger, error) {
+	return config.logger()
 }
 
-func NewLogConfig() *logConfig {
-	return &logConfig{
+func NewLogConfig() *Config {
+	return &Config{
 		Level:                 DebugLevel,
 		EnableColors:          true,
 		CrashLogFilename:      "./logs/crash.log",
@@ -90,30 +136,148 @@ func NewLogConfig() *logConfig {
 	}
 }
 
-func (config *logConfig) newLogger() *zap.Logger {
-	if config.CrashLogFilename != "" {
-		writeCrashLog(config.CrashLogFilename)
+// globalLogger is the value held by _logger, it is never modified once stored.
+type globalLogger struct {
+	*Logger
+
+	// base is the logger installed by Init writing to root through a
+	// swapCore, Logger is base with fields added.
+	base   *Logger
+	root   *rootCore
+	fields []interface{}
+}
+
+// _globalMu serializes the updates of _logger, readers only load it.
+var _globalMu sync.Mutex
+
+func getLogger() *Logger {
+	return _logger.Load().(*globalLogger).Logger
+}
+
+func pkgLogger() *zap.SugaredLogger {
+	return _logger.Load().(*globalLogger).skip
+}
+
+// setLogger installs l as the global logger, keeping the fields set by Fields.
+// The loggers derived from the global logger, e.g. by Get, write to the cores
+// of l from now on.
+func setLogger(l *Logger) {
+	_globalMu.Lock()
+	defer _globalMu.Unlock()
+
+	prev, _ := _logger.Load().(*globalLogger)
+	var fields []interface{}
+	if prev != nil {
+		fields = prev.fields
 	}
 
-	config.atomicLevel = zap.NewAtomicLevelAt(config.Level)
-	config.initColor()
+	root := &rootCore{Core: l.Desugar().Core()}
+	base := l.derive(l.Desugar().WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		return &swapCore{}
+	})).Sugar())
 
-	cores := []zapcore.Core{
-		zapcore.NewCore(
-			encoder.NewTextEncoder(config.encoderConfig()),
-			zapcore.Lock(os.Stdout),
-			config.atomicLevel,
-		)}
+	storeLogger(base, root, fields)
+	rebuildNamed(base)
 
-	if config.Filename != "" {
-		cores = append(cores, config.fileCore())
+	// write what the previous cores hold back, e.g. deduplicated entries,
+	// before their files get closed
+	if prev != nil {
+		_ = prev.root.Sync()
 	}
+}
 
-	if config.ErrorLogFilename != "" {
-		cores = append(cores, config.errorFileCore())
+// ReplaceGlobal installs l as the global logger, keeping the fields set by
+// Fields, and returns a function restoring the previous global logger and its
+// fields.
+func ReplaceGlobal(l *Logger) (restore func()) {
+	prev := _logger.Load().(*globalLogger)
+	setLogger(l)
+
+	return func() {
+		_globalMu.Lock()
+		defer _globalMu.Unlock()
+
+		storeLogger(prev.base, prev.root, prev.fields)
+		rebuildNamed(prev.base)
+	}
+}
+
+// storeLogger must be called with _globalMu held.
+func storeLogger(base *Logger, root *rootCore, fields []interface{}) {
+	l := base
+	if len(fields) > 0 {
+		l = base.With(fields...)
 	}
 
+	_root.Store(root)
+	_logger.Store(&globalLogger{
+		Logger: l,
+		base:   base,
+		root:   root,
+		fields: fields,
+	})
+}
+
+func (config *Config) logger() (*Logger, error) {
+	l, err := config.newLogger()
+	if err != nil {
+		return nil, err
+	}
+
+	return config.wrap(l), nil
+}
+
+func (config *Config) wrap(l *zap.Logger) *Logger {
+	base := &Logger{
+		level:     config.atomicLevel,
+		levels:    config.levels,
+		elevation: &elevation{level: config.atomicLevel},
+		sampling:  config.sampling,
+	}
+
+	return base.derive(l.Sugar())
+}
+
+func (config *Config) newLogger() (*zap.Logger, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.CrashLogFilename != "" {
+		if err := writeCrashLog(config.CrashLogFilename); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(config.Outputs) > 0 {
+		return config.build(config.outputCores()), nil
+	}
+
+	return config.build(config.defaultCores()), nil
+}
+
+// NewWithCores builds a logger from config writing to cores instead of the
+// configured destinations, e.g. to record the entries in tests. Only the
+// level, name, fields, caller and sampling settings of config apply.
+func (config *Config) NewWithCores(cores ...zapcore.Core) (*Logger, error) {
+	if err := config.validateLogger(); err != nil {
+		return nil, fmt.Errorf("invalid log config. %w", err)
+	}
+
+	return config.wrap(config.build(cores)), nil
+}
+
+// build wraps cores with the sampling, levels and options of config.
+func (config *Config) build(cores []zapcore.Core) *zap.Logger {
+	config.atomicLevel = zap.NewAtomicLevelAt(config.Level)
+	config.levels = newNameLevels(config.Levels)
+
 	core := zapcore.NewTee(cores...)
+	config.sampling = nil
+	if config.Sampling != nil {
+		core, config.sampling = config.Sampling.sample(core)
+	}
+	core = newNameLevelCore(core, config.atomicLevel, config.levels)
 
 	var options []zap.Option
 
@@ -125,6 +289,10 @@ func (config *logConfig) newLogger() *zap.Logger {
 		options = append(options, zap.AddStacktrace(zapcore.ErrorLevel))
 	}
 
+	if config.Development {
+		options = append(options, zap.Development())
+	}
+
 	zapLog := zap.New(core, options...)
 
 	if config.Name != "" {
@@ -134,10 +302,33 @@ func (config *logConfig) newLogger() *zap.Logger {
 	return zapLog.With(config.Fields...)
 }
 
-func (config *logConfig) encoderConfig() zapcore.EncoderConfig {
+// defaultCores writes to stdout, Filename and ErrorLogFilename, it is used
+// when no Outputs are configured.
+func (config *Config) defaultCores() []zapcore.Core {
+	cores := []zapcore.Core{
+		config.newCore(
+			encoder.NewTextEncoder(config.encoderConfig(config.EnableColors)),
+			zapcore.Lock(os.Stdout),
+			_allLevels,
+		)}
+
+	// the embedded lumberjack.Logger may be left unset, e.g. in a Config
+	// declared by hand.
+	if config.Logger != nil && config.Filename != "" {
+		cores = append(cores, config.fileCore())
+	}
+
+	if config.ErrorLogFilename != "" {
+		cores = append(cores, config.errorFileCore())
+	}
+
+	return cores
+}
+
+func (config *Config) encoderConfig(colors bool) zapcore.EncoderConfig {
 	el := config.encodeLevel
-	if config.EnableColors {
-		el = config.encodeColorLevel
+	if colors {
+		el = config.colorLevelEncoder()
 	}
 
 	return zapcore.EncoderConfig{
@@ -158,35 +349,49 @@ func (config *logConfig) encoderConfig() zapcore.EncoderConfig {
 		},
 		EncodeDuration: zapcore.StringDurationEncoder,
 		EncodeCaller: func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+			if config.EnableFullCaller {
+				enc.AppendString(" " + caller.FullPath())
+				return
+			}
 			enc.AppendString(trimCallerFilePath(caller))
 		},
 	}
 }
 
-func (config *logConfig) fileCore() zapcore.Core {
-	return zapcore.NewCore(
-		encoder.NewTextEncoder(config.encoderConfig()),
+// newCore builds a default core, collapsing the repeated entries when Dedup is
+// set.
+func (config *Config) newCore(enc zapcore.Encoder, ws zapcore.WriteSyncer, enab zapcore.LevelEnabler) zapcore.Core {
+	if config.Dedup > 0 {
+		return newDedupCore(enc, ws, enab, time.Duration(config.Dedup))
+	}
+
+	return zapcore.NewCore(enc, ws, enab)
+}
+
+func (config *Config) fileCore() zapcore.Core {
+	return config.newCore(
+		encoder.NewTextEncoder(config.encoderConfig(config.EnableColors)),
 		// zapcore.NewMultiWriteSyncer(
 		// 	zapcore.Lock(os.Stdout),
 		// 	config.fileWriteSyncer(),
 		// ),
-		config.fileWriteSyncer(config.Filename),
-		config.atomicLevel,
+		config.fileWriteSyncer(config.Filename, nil),
+		_allLevels,
 	)
 }
 
-func (config *logConfig) errorFileCore() zapcore.Core {
-	return zapcore.NewCore(
-		encoder.NewTextEncoder(config.encoderConfig()),
+func (config *Config) errorFileCore() zapcore.Core {
+	return config.newCore(
+		encoder.NewTextEncoder(config.encoderConfig(config.EnableColors)),
 
-		config.fileWriteSyncer(config.ErrorLogFilename),
+		config.fileWriteSyncer(config.ErrorLogFilename, nil),
 		zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
 			return lvl >= zapcore.ErrorLevel
 		}),
 	)
 }
 
-func (config *logConfig) encodeLevel(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+func (config *Config) encodeLevel(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
 	levelString := l.CapitalString()
 
 	if config.EnableLevelTruncation {
@@ -196,15 +401,6 @@ func (config *logConfig) encodeLevel(l zapcore.Level, enc zapcore.PrimitiveArray
 	enc.AppendString(fmt.Sprintf("[%s]", levelString))
 }
 
-func (config *logConfig) encodeColorLevel(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
-	s, ok := _levelToColorStrings[l]
-	if !ok {
-		s = _unknownLevelColor.Add(l.CapitalString())
-	}
-
-	enc.AppendString(fmt.Sprintf("[%s]", s))
-}
-
 func trimCallerFilePath(ec zapcore.EntryCaller) string {
 	if !ec.Defined {
 		return "undefined"
@@ -225,7 +421,16 @@ func trimCallerFilePath(ec zapcore.EntryCaller) string {
 	return fmt.Sprintf(" %s", caller)
 }
 
-func (config *logConfig) fileWriteSyncer(fileName string) zapcore.WriteSyncer {
+// fileWriteSyncer opens fileName rotated as described by rotation, the
+// embedded lumberjack.Logger of config is used when rotation is nil.
+func (config *Config) fileWriteSyncer(fileName string, rotation *lumberjack.Logger) zapcore.WriteSyncer {
+	if rotation == nil {
+		rotation = config.Logger
+	}
+	if rotation == nil {
+		rotation = &lumberjack.Logger{LocalTime: true}
+	}
+
 	// go get github.com/lestrrat-go/file-rotatelogs
 	// writer, err := rotatelogs.New(
 	// 	name+".%Y%m%d",
@@ -237,38 +442,125 @@ func (config *logConfig) fileWriteSyncer(fileName string) zapcore.WriteSyncer {
 	// 	log.Fatalf("config normal logger file error. %v", errors.WithStack(err))
 	// }
 
-	writer := &lumberjack.Logger{
-		Filename:         fileName,
-		MaxSize:          config.MaxSize, // 单个日志文件大小（MB）
-		MaxBackups:       config.MaxBackups,
-		MaxAge:           config.MaxAge, // 保留多少天的日志
-		LocalTime:        config.LocalTime,
-		Compress:         config.Compress,
-		BackupTimeFormat: config.BackupTimeFormat,
+	// lumberjack truncates a file it creates and writes it from its own
+	// offset, create it beforehand so that the writers sharing it append
+	_ = createFile(fileName)
+
+	writer := &fileWriter{
+		Logger: &lumberjack.Logger{
+			Filename:         fileName,
+			MaxSize:          rotation.MaxSize, // 单个日志文件大小（MB）
+			MaxBackups:       rotation.MaxBackups,
+			MaxAge:           rotation.MaxAge, // 保留多少天的日志
+			LocalTime:        rotation.LocalTime,
+			Compress:         rotation.Compress,
+			BackupTimeFormat: rotation.BackupTimeFormat,
+		},
 	}
 
 	// Rotating log files daily
-	runner := cron.New(cron.WithSeconds(), cron.WithLocation(time.Local))
-	_, _ = runner.AddFunc("0 0 0 * * ?", func() {
+	writer.runner = cron.New(cron.WithSeconds(), cron.WithLocation(time.Local))
+	_, _ = writer.runner.AddFunc("0 0 0 * * ?", func() {
 		_ = writer.Rotate(time.Now().AddDate(0, 0, -1))
 	})
-	go runner.Run()
+	go writer.runner.Run()
+
+	config.writers = append(config.writers, writer)
+
+	_writers.Lock()
+	_writers.m[writer] = struct{}{}
+	_writers.Unlock()
 
 	return zapcore.AddSync(writer)
 }
 
-func writeCrashLog(file string) {
+// createFile creates file and its directory when missing.
+func createFile(file string) error {
+	if err := os.MkdirAll(path.Dir(file), os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// fileWriter is a rotating log file together with the cron runner that rotates
+// it daily.
+type fileWriter struct {
+	*lumberjack.Logger
+	runner *cron.Cron
+
+	// closed is set by Close, a write still in flight then reopens the file,
+	// which is closed again right after.
+	closed uint32
+}
+
+func (w *fileWriter) Write(p []byte) (int, error) {
+	n, err := w.Logger.Write(p)
+	if atomic.LoadUint32(&w.closed) == 1 {
+		_ = w.Logger.Close()
+	}
+
+	return n, err
+}
+
+// _writers tracks every open fileWriter so that they can be reopened at once.
+var _writers = struct {
+	sync.Mutex
+	m map[*fileWriter]struct{}
+}{m: make(map[*fileWriter]struct{})}
+
+func (w *fileWriter) Close() error {
+	atomic.StoreUint32(&w.closed, 1)
+
+	_writers.Lock()
+	delete(_writers.m, w)
+	_writers.Unlock()
+
+	w.runner.Stop()
+	return w.Logger.Close()
+}
+
+// reopenFiles closes the files of every open fileWriter, each of them opens its
+// file again by name on its next write. It returns the number of writers.
+func reopenFiles() int {
+	_writers.Lock()
+	defer _writers.Unlock()
+
+	for w := range _writers.m {
+		_ = w.Logger.Close()
+	}
+
+	return len(_writers.m)
+}
+
+func writeCrashLog(file string) error {
 	err := os.MkdirAll(path.Dir(file), os.ModePerm)
 	if err != nil {
-		log.Fatalf("make crash log dir error. %v",
-			err)
+		return fmt.Errorf("make crash log dir error. %w", err)
 	}
 
-	crash.NewCrashLog(file)
+	return crash.NewCrashLog(file)
 }
 
+// Fields replaces the fields added to every entry of the global logger, on top
+// of the Fields of its Config. They are kept when Init installs a new global
+// logger.
 func Fields(args ...interface{}) {
-	logger = logger.With(args...)
+	_globalMu.Lock()
+	defer _globalMu.Unlock()
+
+	g := _logger.Load().(*globalLogger)
+	storeLogger(g.base, g.root, append([]interface{}(nil), args...))
+}
+
+// ResetFields removes the fields set by Fields from the global logger.
+func ResetFields() {
+	Fields()
 }
 
 func With(l *zap.SugaredLogger, args ...interface{}) *zap.SugaredLogger {
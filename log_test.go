@@ -1,12 +1,49 @@
 package log
 
 import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
-	"time"
 
 	"go.uber.org/zap"
 )
 
+// keepGlobal restores the global logger, its fields and the loggers registered
+// by Get when t ends.
+func keepGlobal(t *testing.T) {
+	prev := _logger.Load()
+
+	_registry.Lock()
+	loggers := make(map[string]*namedLogger, len(_registry.loggers))
+	for name, n := range _registry.loggers {
+		loggers[name] = n
+	}
+	_registry.Unlock()
+
+	t.Cleanup(func() {
+		_root.Store(prev.(*globalLogger).root)
+		_logger.Store(prev)
+
+		_registry.Lock()
+		_registry.loggers = loggers
+		_registry.Unlock()
+	})
+}
+
+// newTestConfig returns NewLogConfig() without crash log, writing only to
+// app.log in a temporary directory, and the path of app.log.
+func newTestConfig(t testing.TB) (*Config, string) {
+	file := filepath.Join(t.TempDir(), "app.log")
+
+	config := NewLogConfig()
+	config.CrashLogFilename = ""
+	config.Outputs = []OutputConfig{{Destination: file}}
+
+	return config, file
+}
+
 func TestLog(t *testing.T) {
 	Debugf("this is %s message", "debug")
 	Infof("this is %s message", "info")
@@ -15,16 +52,22 @@ func TestLog(t *testing.T) {
 }
 
 func TestLogWithConfig(t *testing.T) {
+	keepGlobal(t)
+
 	config := NewLogConfig()
 	_ = config.Level.Set("debug")
 	config.Name = "main"
 	// config.Fields = []zap.Field{zap.String("traceid", "12123123123")}
 
-	config.Init()
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
 
 	Fields("traceid", float64(21221212122))
 	Debugf("this is %s message", "debug")
-	config.Init()
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
 	Fields(zap.String("traceid", "12123123123"))
 	Infof("this is %s message", "info")
 	// Errorf("this is %s message", "error")
@@ -36,21 +79,157 @@ func TestLogWithNew(t *testing.T) {
 	_ = config.Level.Set("debug")
 	config.Name = "main"
 
-	logger := config.New()
+	logger, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	log := With(logger, "traceid", float64(21221212122), "request", "[POST]/hello/v2")
+	log := With(logger.SugaredLogger, "traceid", float64(21221212122), "request", "[POST]/hello/v2")
 	log.Debugf("this is %s message", "debug")
 	log.Infof("this is %s message", "info")
+
+	logger.SetLevel(InfoLevel)
+	if logger.Enabled(DebugLevel) || !logger.Named("child").Enabled(InfoLevel) {
+		t.Error("the logger built by New should follow SetLevel")
+	}
 }
 
 func TestLogRote(t *testing.T) {
-	lc := NewLogConfig()
+	keepGlobal(t)
+
+	lc, file := newTestConfig(t)
 	lc.MaxSize = 1
 
-	lc.Init()
+	if err := lc.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := strings.Repeat("x", 1024)
+	for i := 0; i < 1200; i++ {
+		Infof("this is %s message", msg)
+	}
+
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(file), "app*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 2 {
+		t.Errorf("expect a rotated file beside the log file, got %v", files)
+	}
+}
+
+func TestLogSugaredSurface(t *testing.T) {
+	keepGlobal(t)
+
+	config, file := newTestConfig(t)
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// each entry is attributed to the line calling the package function.
+	var want []string
+	want = append(want, callerLine(t)+"{k=v} - debugw message\n")
+	Debugw("debugw message", "k", "v")
+	want = append(want, callerLine(t)+"{n=1} - infow message\n")
+	Infow("infow message", "n", 1)
+	want = append(want, callerLine(t)+"- warnln message 2\n")
+	Warnln("warnln", "message", 2)
+	want = append(want, callerLine(t)+"- errorw message\n")
+	Errorw("errorw message")
+	want = append(want, callerLine(t)+"{k=v} - dpanicw message\n")
+	DPanicw("dpanicw message", "k", "v")
+	want = append(want, callerLine(t)+"- print message\n")
+	Print("print ", "message")
+	want = append(want, callerLine(t)+"- printf message\n")
+	Printf("printf %s", "message")
+	want = append(want, callerLine(t)+"- println message\n")
+	Println("println", "message")
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Panicw should panic")
+			}
+		}()
+		want = append(want, callerLine(t)+"{k=v} - panicw message\n")
+		Panicw("panicw message", "k", "v")
+	}()
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, s := range want {
+		if !strings.Contains(out, s) {
+			t.Errorf("missing %q in %q", s, out)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	keepGlobal(t)
+
+	config, file := newTestConfig(t)
+	config.Fields = []zap.Field{zap.String("app", "demo")}
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	Fields("a", 1)
+	Fields("b", 2)
+	Info("replaced")
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+	Info("after init")
+	ResetFields()
+	Info("reset")
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, s := range []string{
+		"{app=demo, b=2} - replaced\n",
+		"{app=demo, b=2} - after init\n",
+		"{app=demo} - reset\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("missing %q in %q", s, out)
+		}
+	}
+}
+
+func TestFieldsConcurrent(t *testing.T) {
+	keepGlobal(t)
+
+	config, _ := newTestConfig(t)
+	config.Outputs[0].EnableColors = true
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
 
-	for {
-		Infof("this is %s message", "info")
-		time.Sleep(time.Millisecond * 1)
+			for j := 0; j < 100; j++ {
+				switch {
+				case i == 0 && j%10 == 0:
+					_ = config.Init()
+				case i < 3:
+					Fields("goroutine", i, "j", j)
+				case i == 3:
+					ResetFields()
+				default:
+					Infow("concurrent", "i", i, "j", j)
+				}
+			}
+		}(i)
 	}
+	wg.Wait()
 }
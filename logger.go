@@ -1,57 +1,177 @@
 package log
 
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Logger is a *zap.SugaredLogger that keeps a handle on the levels of the
+// Config it was built from.
+type Logger struct {
+	*zap.SugaredLogger
+	level dynamicLevel
+	// levels is nil for the loggers registered by Get, which follow the
+	// overrides of the global logger.
+	levels    *nameLevels
+	elevation *elevation
+	sampling  *samplingCounters
+
+	// skip skips the frame of the Logger methods wrapping it, so that entries
+	// are attributed to their callers.
+	skip *zap.SugaredLogger
+}
+
+// With adds a variadic number of fields to the logging context, see
+// zap.SugaredLogger.With.
+func (l *Logger) With(args ...interface{}) *Logger {
+	return l.derive(l.SugaredLogger.With(args...))
+}
+
+// Named adds a sub-scope to the logger's name, see zap.SugaredLogger.Named.
+func (l *Logger) Named(name string) *Logger {
+	return l.derive(l.SugaredLogger.Named(name))
+}
+
+func (l *Logger) derive(s *zap.SugaredLogger) *Logger {
+	return &Logger{
+		SugaredLogger: s,
+		level:         l.level,
+		levels:        l.levels,
+		elevation:     l.elevation,
+		sampling:      l.sampling,
+		skip:          s.Desugar().WithOptions(zap.AddCallerSkip(1)).Sugar(),
+	}
+}
+
 func Debug(args ...interface{}) {
-	logger.Debug(args...)
+	pkgLogger().Debug(args...)
 }
 
 func Debugf(template string, args ...interface{}) {
-	logger.Debugf(template, args...)
+	pkgLogger().Debugf(template, args...)
+}
+
+func Debugw(msg string, keysAndValues ...interface{}) {
+	pkgLogger().Debugw(msg, keysAndValues...)
+}
+
+func Debugln(args ...interface{}) {
+	pkgLogger().Debug(sprintln(args...))
 }
 
 func Info(args ...interface{}) {
-	logger.Info(args...)
+	pkgLogger().Info(args...)
 }
 
 func Infof(template string, args ...interface{}) {
-	logger.Infof(template, args...)
+	pkgLogger().Infof(template, args...)
+}
+
+func Infow(msg string, keysAndValues ...interface{}) {
+	pkgLogger().Infow(msg, keysAndValues...)
+}
+
+func Infoln(args ...interface{}) {
+	pkgLogger().Info(sprintln(args...))
 }
 
 func Warn(args ...interface{}) {
-	logger.Warn(args...)
+	pkgLogger().Warn(args...)
 }
 
 func Warnf(template string, args ...interface{}) {
-	logger.Warnf(template, args...)
+	pkgLogger().Warnf(template, args...)
+}
+
+func Warnw(msg string, keysAndValues ...interface{}) {
+	pkgLogger().Warnw(msg, keysAndValues...)
+}
+
+func Warnln(args ...interface{}) {
+	pkgLogger().Warn(sprintln(args...))
 }
 
 func Error(args ...interface{}) {
-	logger.Error(args...)
+	pkgLogger().Error(args...)
 }
 
 func Errorf(template string, args ...interface{}) {
-	logger.Errorf(template, args...)
+	pkgLogger().Errorf(template, args...)
+}
+
+func Errorw(msg string, keysAndValues ...interface{}) {
+	pkgLogger().Errorw(msg, keysAndValues...)
+}
+
+func Errorln(args ...interface{}) {
+	pkgLogger().Error(sprintln(args...))
 }
 
 func DPanic(args ...interface{}) {
-	logger.DPanic(args...)
+	pkgLogger().DPanic(args...)
 }
 
 func DPanicf(template string, args ...interface{}) {
-	logger.DPanicf(template, args...)
+	pkgLogger().DPanicf(template, args...)
+}
+
+func DPanicw(msg string, keysAndValues ...interface{}) {
+	pkgLogger().DPanicw(msg, keysAndValues...)
+}
+
+func DPanicln(args ...interface{}) {
+	pkgLogger().DPanic(sprintln(args...))
 }
 
 func Panic(args ...interface{}) {
-	logger.Panic(args...)
+	pkgLogger().Panic(args...)
 }
 
 func Panicf(template string, args ...interface{}) {
-	logger.Panicf(template, args...)
+	pkgLogger().Panicf(template, args...)
+}
+
+func Panicw(msg string, keysAndValues ...interface{}) {
+	pkgLogger().Panicw(msg, keysAndValues...)
+}
+
+func Panicln(args ...interface{}) {
+	pkgLogger().Panic(sprintln(args...))
 }
 
 func Fatal(args ...interface{}) {
-	logger.Fatal(args...)
+	pkgLogger().Fatal(args...)
 }
 
 func Fatalf(template string, args ...interface{}) {
-	logger.Fatalf(template, args...)
+	pkgLogger().Fatalf(template, args...)
+}
+
+func Fatalw(msg string, keysAndValues ...interface{}) {
+	pkgLogger().Fatalw(msg, keysAndValues...)
+}
+
+func Fatalln(args ...interface{}) {
+	pkgLogger().Fatal(sprintln(args...))
+}
+
+// Print logs at InfoLevel, it makes the package a drop-in replacement for the
+// standard library log package.
+func Print(args ...interface{}) {
+	pkgLogger().Info(args...)
+}
+
+func Printf(template string, args ...interface{}) {
+	pkgLogger().Infof(template, args...)
+}
+
+func Println(args ...interface{}) {
+	pkgLogger().Info(sprintln(args...))
+}
+
+// sprintln formats args like fmt.Sprintln, without the trailing newline.
+func sprintln(args ...interface{}) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
 }
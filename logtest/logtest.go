@@ -0,0 +1,120 @@
+// Package logtest records the entries of the loggers of package log in memory,
+// so that tests can assert on them.
+package logtest
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/ehlxr/log"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Recorder holds the entries written to a logger built by New or Capture.
+type Recorder struct {
+	*observer.ObservedLogs
+	t testing.TB
+}
+
+// New builds a logger from config writing to a Recorder only, the
+// destinations of config are ignored. A nil config means log.NewLogConfig().
+func New(t testing.TB, config *log.Config) (*log.Logger, *Recorder) {
+	t.Helper()
+
+	if config == nil {
+		config = log.NewLogConfig()
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	l, err := config.NewWithCores(core)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return l, &Recorder{ObservedLogs: logs, t: t}
+}
+
+// Capture replaces the global logger with one built by New from
+// log.NewLogConfig() until t ends.
+func Capture(t testing.TB) *Recorder {
+	t.Helper()
+
+	l, r := New(t, nil)
+	t.Cleanup(log.ReplaceGlobal(l))
+
+	return r
+}
+
+// AssertLogged fails the test unless an entry has level and msg and carries
+// keysAndValues, compared by their fmt.Sprint form. It returns the first
+// matching entry.
+func (r *Recorder) AssertLogged(level zapcore.Level, msg string, keysAndValues ...interface{}) observer.LoggedEntry {
+	r.t.Helper()
+
+	if len(keysAndValues)%2 != 0 {
+		r.t.Fatalf("odd number of keysAndValues %v", keysAndValues)
+	}
+
+	for _, e := range r.All() {
+		if e.Level == level && e.Message == msg && hasFields(e, keysAndValues) {
+			return e
+		}
+	}
+
+	r.t.Errorf("no %s entry %q with %v in %s", level, msg, keysAndValues, r)
+	return observer.LoggedEntry{}
+}
+
+// AssertNotLogged fails the test if an entry has msg.
+func (r *Recorder) AssertNotLogged(msg string) {
+	r.t.Helper()
+
+	if n := r.FilterMessage(msg).Len(); n > 0 {
+		r.t.Errorf("unexpected %d entries %q in %s", n, msg, r)
+	}
+}
+
+// AssertName fails the test unless e was written by the logger named name,
+// e.g. "[main].db".
+func (r *Recorder) AssertName(e observer.LoggedEntry, name string) {
+	r.t.Helper()
+
+	if e.LoggerName != name {
+		r.t.Errorf("entry %q logged by %q, want %q", e.Message, e.LoggerName, name)
+	}
+}
+
+// AssertCaller fails the test unless e was written from the file with the
+// base name file, at line unless it is 0.
+func (r *Recorder) AssertCaller(e observer.LoggedEntry, file string, line int) {
+	r.t.Helper()
+
+	c := e.Caller
+	if !c.Defined || filepath.Base(c.File) != file || (line != 0 && c.Line != line) {
+		r.t.Errorf("entry %q logged from %s, want %s:%d", e.Message, c.TrimmedPath(), file, line)
+	}
+}
+
+// String lists the recorded entries, one per line.
+func (r *Recorder) String() string {
+	s := ""
+	for _, e := range r.All() {
+		s += fmt.Sprintf("\n\t%s %q %v", e.Level, e.Message, e.ContextMap())
+	}
+
+	return s
+}
+
+func hasFields(e observer.LoggedEntry, keysAndValues []interface{}) bool {
+	fields := e.ContextMap()
+	for i := 0; i < len(keysAndValues); i += 2 {
+		v, ok := fields[fmt.Sprint(keysAndValues[i])]
+		if !ok || fmt.Sprint(v) != fmt.Sprint(keysAndValues[i+1]) {
+			return false
+		}
+	}
+
+	return true
+}
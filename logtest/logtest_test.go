@@ -0,0 +1,60 @@
+package logtest
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/ehlxr/log"
+)
+
+func TestNew(t *testing.T) {
+	config := log.NewLogConfig()
+	config.Level = log.InfoLevel
+	config.Name = "main"
+
+	l, r := New(t, config)
+	l.Debug("debug message")
+	l.Named("db").Infow("info message", "k", "v", "n", 1)
+
+	e := r.AssertLogged(log.InfoLevel, "info message", "k", "v", "n", 1)
+	r.AssertName(e, "[main].db")
+	r.AssertCaller(e, "logtest_test.go", 0)
+	r.AssertNotLogged("debug message")
+
+	if r.Len() != 1 {
+		t.Errorf("expect 1 entry, got %s", r)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	r := Capture(t)
+
+	_, _, line, _ := runtime.Caller(0)
+	log.Warnf("warn %d", 1)
+
+	r.AssertCaller(r.AssertLogged(log.WarnLevel, "warn 1"), "logtest_test.go", line+1)
+}
+
+func TestAssertFailures(t *testing.T) {
+	rec := &recordingT{TB: t}
+	l, r := New(rec, nil)
+	l.Info("info message")
+
+	e := r.AssertLogged(log.InfoLevel, "info message", "k", "v")
+	r.AssertLogged(log.ErrorLevel, "info message")
+	r.AssertNotLogged("info message")
+	r.AssertName(e, "db")
+	r.AssertCaller(e, "other.go", 0)
+
+	if rec.errors != 5 {
+		t.Errorf("expect 5 failed assertions, got %d", rec.errors)
+	}
+}
+
+// recordingT counts the failed assertions instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors int
+}
+
+func (t *recordingT) Errorf(string, ...interface{}) { t.errors++ }
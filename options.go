@@ -0,0 +1,77 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Option configures the Config used by New.
+type Option func(*Config)
+
+// New builds a Logger from NewLogConfig() with opts applied in order.
+func New(opts ...Option) (*Logger, error) {
+	config := NewLogConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config.New()
+}
+
+// WithLevel sets the minimum enabled level.
+func WithLevel(level zapcore.Level) Option {
+	return func(config *Config) {
+		config.Level = level
+	}
+}
+
+// WithLevels sets the logger name level overrides, see Config.Levels.
+func WithLevels(spec string) Option {
+	return func(config *Config) {
+		config.Levels = spec
+	}
+}
+
+// WithFile sets the file all enabled entries are written to, "" disables it.
+func WithFile(file string) Option {
+	return func(config *Config) {
+		config.Filename = file
+	}
+}
+
+// WithErrorFile sets the file error and above entries are written to, ""
+// disables it.
+func WithErrorFile(file string) Option {
+	return func(config *Config) {
+		config.ErrorLogFilename = file
+	}
+}
+
+// WithCrashFile sets the file the process stderr is redirected to, "" disables
+// the redirection.
+func WithCrashFile(file string) Option {
+	return func(config *Config) {
+		config.CrashLogFilename = file
+	}
+}
+
+// WithColors enables the colorized level text.
+func WithColors(enable bool) Option {
+	return func(config *Config) {
+		config.EnableColors = enable
+	}
+}
+
+// WithName sets the name of the logger, written as "[name]".
+func WithName(name string) Option {
+	return func(config *Config) {
+		config.Name = name
+	}
+}
+
+// WithFields appends fields added to every entry.
+func WithFields(fields ...zap.Field) Option {
+	return func(config *Config) {
+		config.Fields = append(config.Fields, fields...)
+	}
+}
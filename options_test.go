@@ -0,0 +1,47 @@
+package log
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "app.log")
+	l, err := New(
+		WithLevel(WarnLevel),
+		WithFile(file),
+		WithErrorFile(""),
+		WithCrashFile(""),
+		WithColors(false),
+		WithName("svc"),
+		WithFields(zap.String("app", "demo")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Infof("dropped")
+	l.Named("db").With("id", 1).Warnf("kept")
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	if strings.Contains(out, "dropped") {
+		t.Errorf("info entry should be disabled: %q", out)
+	}
+	if !strings.Contains(out, "[WARN][svc].db") || !strings.Contains(out, "{app=demo, id=1}") || !strings.Contains(out, "kept") {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	if _, err := New(WithLevel(FatalLevel + 1)); err == nil {
+		t.Error("expected invalid level error")
+	}
+}
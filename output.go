@@ -0,0 +1,133 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ehlxr/log/encoder"
+	"github.com/ehlxr/lumberjack"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// OutputConfig describes one destination of the log entries.
+type OutputConfig struct {
+	// Destination is "stdout", "stderr" or the path of a log file.
+	Destination string `json:"destination" yaml:"destination" toml:"destination"`
+
+	// MinLevel and MaxLevel narrow the levels written to this output, entries
+	// must be enabled by Config.Level (or Config.Levels) as well. Unset means
+	// unbounded.
+	MinLevel *zapcore.Level `json:"minLevel,omitempty" yaml:"minLevel,omitempty" toml:"minLevel,omitempty"`
+	MaxLevel *zapcore.Level `json:"maxLevel,omitempty" yaml:"maxLevel,omitempty" toml:"maxLevel,omitempty"`
+
+	// Encoding is "text" (the default) or "json".
+	Encoding     string `json:"encoding,omitempty" yaml:"encoding,omitempty" toml:"encoding,omitempty"`
+	EnableColors bool   `json:"enableColors,omitempty" yaml:"enableColors,omitempty" toml:"enableColors,omitempty"`
+
+	// Dedup collapses the entries identical to the previous one, but for
+	// their time, written within Dedup of it into a "last message repeated N
+	// times" entry. Unset disables it.
+	Dedup Duration `json:"dedup,omitempty" yaml:"dedup,omitempty" toml:"dedup,omitempty"`
+
+	// Rotation overrides the rotation policy of the embedded lumberjack.Logger
+	// of Config for a file destination, its Filename is ignored.
+	Rotation *lumberjack.Logger `json:"rotation,omitempty" yaml:"rotation,omitempty" toml:"rotation,omitempty"`
+}
+
+func (config *Config) outputCores() []zapcore.Core {
+	cores := make([]zapcore.Core, 0, len(config.Outputs))
+	for _, output := range config.Outputs {
+		var ws zapcore.WriteSyncer
+		switch output.Destination {
+		case "stdout":
+			ws = zapcore.Lock(os.Stdout)
+		case "stderr":
+			ws = zapcore.Lock(os.Stderr)
+		default:
+			ws = config.fileWriteSyncer(output.Destination, output.Rotation)
+		}
+
+		var enc zapcore.Encoder
+		if output.Encoding == "json" {
+			enc = zapcore.NewJSONEncoder(config.jsonEncoderConfig())
+		} else {
+			enc = encoder.NewTextEncoder(config.encoderConfig(output.EnableColors))
+		}
+
+		if output.Dedup > 0 {
+			cores = append(cores, newDedupCore(enc, ws, output.levelEnabler(), time.Duration(output.Dedup)))
+		} else {
+			cores = append(cores, zapcore.NewCore(enc, ws, output.levelEnabler()))
+		}
+	}
+
+	return cores
+}
+
+func (output OutputConfig) levelEnabler() zapcore.LevelEnabler {
+	return zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		if output.MinLevel != nil && lvl < *output.MinLevel {
+			return false
+		}
+
+		return output.MaxLevel == nil || lvl <= *output.MaxLevel
+	})
+}
+
+func (config *Config) jsonEncoderConfig() zapcore.EncoderConfig {
+	el := zapcore.LowercaseLevelEncoder
+	if config.EnableCapitalLevel {
+		el = zapcore.CapitalLevelEncoder
+	}
+
+	ec := zapcore.ShortCallerEncoder
+	if config.EnableFullCaller {
+		ec = zapcore.FullCallerEncoder
+	}
+
+	return zapcore.EncoderConfig{
+		TimeKey:       "time",
+		LevelKey:      "level",
+		NameKey:       "logger",
+		CallerKey:     "caller",
+		MessageKey:    "msg",
+		StacktraceKey: "stacktrace",
+		LineEnding:    zapcore.DefaultLineEnding,
+		EncodeLevel:   el,
+		EncodeTime: func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+			enc.AppendString(t.Format(config.TimestampFormat))
+		},
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   ec,
+	}
+}
+
+func (output OutputConfig) validate(i int) error {
+	var errs error
+
+	if output.Destination == "" {
+		errs = multierr.Append(errs, fmt.Errorf("outputs[%d] destination must not be empty", i))
+	}
+	if output.Encoding != "" && output.Encoding != "text" && output.Encoding != "json" {
+		errs = multierr.Append(errs, fmt.Errorf("outputs[%d] unknown encoding %q", i, output.Encoding))
+	}
+	if output.Dedup < 0 {
+		errs = multierr.Append(errs, fmt.Errorf("outputs[%d] dedup must not be negative", i))
+	}
+	if output.MinLevel != nil && output.MaxLevel != nil && *output.MinLevel > *output.MaxLevel {
+		errs = multierr.Append(errs, fmt.Errorf("outputs[%d] minLevel %s is above maxLevel %s", i, output.MinLevel, output.MaxLevel))
+	}
+
+	if output.Destination == "stdout" || output.Destination == "stderr" {
+		return errs
+	}
+
+	if r := output.Rotation; r != nil && (r.MaxSize < 0 || r.MaxAge < 0 || r.MaxBackups < 0) {
+		errs = multierr.Append(errs, fmt.Errorf("outputs[%d] rotation settings must not be negative", i))
+	}
+
+	return multierr.Append(errs, checkWritable(fmt.Sprintf("outputs[%d]", i), output.Destination))
+}
@@ -0,0 +1,88 @@
+package log
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputs(t *testing.T) {
+	dir := t.TempDir()
+	warnFile := filepath.Join(dir, "warn.log")
+	debugFile := filepath.Join(dir, "debug.log")
+
+	config, err := LoadConfig(writeConfigFile(t, "log.yaml", `
+crashLogFilename: ""
+outputs:
+  - destination: `+warnFile+`
+    minLevel: warn
+    rotation:
+      maxsize: 1
+  - destination: `+debugFile+`
+    minLevel: debug
+    maxLevel: debug
+    encoding: json
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Debugw("debug message", "k", 1)
+	l.Info("info message")
+	l.Warn("warn message")
+	l.Error("error message")
+
+	data, err := ioutil.ReadFile(warnFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	warn := string(data)
+	if strings.Contains(warn, "debug message") || strings.Contains(warn, "info message") ||
+		!strings.Contains(warn, "[WARN]") || !strings.Contains(warn, "error message") {
+		t.Errorf("unexpected warn output %q", warn)
+	}
+
+	data, err = ioutil.ReadFile(debugFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expect only the debug entry, got %q", data)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "DEBUG" || entry["msg"] != "debug message" || entry["k"] != float64(1) {
+		t.Errorf("unexpected json entry %v", entry)
+	}
+}
+
+func TestOutputsValidate(t *testing.T) {
+	warn, info := WarnLevel, InfoLevel
+
+	config := NewLogConfig()
+	config.CrashLogFilename = ""
+	config.Outputs = []OutputConfig{
+		{Destination: ""},
+		{Destination: "stdout", Encoding: "xml"},
+		{Destination: "stderr", MinLevel: &warn, MaxLevel: &info},
+	}
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, s := range []string{"outputs[0] destination", "outputs[1] unknown encoding", "outputs[2] minLevel"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("error does not report %s: %v", s, err)
+		}
+	}
+}
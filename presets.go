@@ -0,0 +1,53 @@
+package log
+
+import (
+	"github.com/ehlxr/lumberjack"
+)
+
+// NewProductionConfig returns a Config suited for production: info level, no
+// colors, JSON entries written to ./logs/log.log and, from error level, to
+// ./logs/error.log, sampling, and stacktraces only for error and above.
+func NewProductionConfig() *Config {
+	errorLevel := ErrorLevel
+
+	return &Config{
+		Level:                 InfoLevel,
+		CrashLogFilename:      "./logs/crash.log",
+		EnableLineNumber:      true,
+		EnableErrorStacktrace: true,
+		TimestampFormat:       "2006-01-02T15:04:05.000Z0700",
+		Sampling: &SamplingConfig{
+			Initial:    100,
+			Thereafter: 100,
+		},
+		Outputs: []OutputConfig{
+			{Destination: "./logs/log.log", Encoding: "json"},
+			{Destination: "./logs/error.log", Encoding: "json", MinLevel: &errorLevel},
+		},
+		Logger: &lumberjack.Logger{
+			MaxSize:          200,
+			MaxBackups:       30,
+			LocalTime:        true,
+			BackupTimeFormat: "2006-01-02",
+		},
+	}
+}
+
+// NewDevelopmentConfig returns a Config suited for development: debug level,
+// colored text on stdout only, full caller paths and DPanic entries panic.
+func NewDevelopmentConfig() *Config {
+	return &Config{
+		Level:                 DebugLevel,
+		EnableColors:          true,
+		EnableLineNumber:      true,
+		EnableLevelTruncation: true,
+		EnableErrorStacktrace: true,
+		TimestampFormat:       "2006-01-02 15:04:05.000",
+		EnableCapitalLevel:    true,
+		EnableFullCaller:      true,
+		Development:           true,
+		Logger: &lumberjack.Logger{
+			LocalTime: true,
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProductionConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	config := NewProductionConfig()
+	config.CrashLogFilename = ""
+	for i := range config.Outputs {
+		config.Outputs[i].Destination = filepath.Join(dir, filepath.Base(config.Outputs[i].Destination))
+	}
+
+	l, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Debug("debug message")
+	for i := 0; i < 300; i++ {
+		l.Infow("repeated message", "i", i)
+	}
+	l.Error("error message")
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "log.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	// 100 initial entries, then every 100th of the remaining 200, then the error
+	if len(lines) != 103 {
+		t.Errorf("expect sampled output of 103 lines, got %d", len(lines))
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "info" || entry["msg"] != "repeated message" || entry["stacktrace"] != nil {
+		t.Errorf("unexpected entry %v", entry)
+	}
+
+	data, err = ioutil.ReadFile(filepath.Join(dir, "error.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["msg"] != "error message" || entry["stacktrace"] == nil {
+		t.Errorf("unexpected error entry %v", entry)
+	}
+}
+
+func TestDevelopmentConfig(t *testing.T) {
+	config := NewDevelopmentConfig()
+	if len(config.Outputs) != 0 || config.Filename != "" || config.ErrorLogFilename != "" || config.CrashLogFilename != "" {
+		t.Errorf("development config should only log to stdout: %+v", config)
+	}
+
+	l, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("DPanic should panic in development mode")
+		}
+	}()
+	l.DPanic("dpanic message")
+}
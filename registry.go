@@ -0,0 +1,197 @@
+package log
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// _registry holds the loggers returned by Get, keyed by name.
+var _registry = struct {
+	sync.RWMutex
+	loggers map[string]*namedLogger
+}{loggers: make(map[string]*namedLogger)}
+
+type namedLogger struct {
+	name   string
+	logger atomic.Value // *Logger
+
+	// level is used instead of the level of the global logger once own is set.
+	level     zap.AtomicLevel
+	own       uint32
+	elevation *elevation
+}
+
+// Get returns the logger registered under name, creating it on first use. It
+// writes to the cores of the global logger, without the fields set by Fields,
+// and adds name to the name of the global logger.
+//
+// The logger follows the level of the global logger, and its Levels, until it
+// is given a level by SetLevel or SetNamedLevel. Init rebuilds the registered
+// loggers with the new name and options, keeping their level. A logger kept
+// from before, e.g. in a package variable, still writes to the current
+// destinations at that level, under the name and options it was built with.
+func Get(name string) *Logger {
+	_registry.RLock()
+	n, ok := _registry.loggers[name]
+	_registry.RUnlock()
+	if ok {
+		return n.logger.Load().(*Logger)
+	}
+
+	_registry.Lock()
+	defer _registry.Unlock()
+
+	if n, ok = _registry.loggers[name]; !ok {
+		n = newNamedLogger(name)
+		n.build(_logger.Load().(*globalLogger).base)
+		_registry.loggers[name] = n
+	}
+
+	return n.logger.Load().(*Logger)
+}
+
+// SetNamedLevel sets the level of the logger registered under name, creating
+// it if needed, see Logger.SetLevel. The global level is left alone.
+func SetNamedLevel(name string, level zapcore.Level) {
+	Get(name).SetLevel(level)
+}
+
+// Names returns the sorted names of the loggers registered by Get.
+func Names() []string {
+	_registry.RLock()
+	defer _registry.RUnlock()
+
+	names := make([]string, 0, len(_registry.loggers))
+	for name := range _registry.loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func newNamedLogger(name string) *namedLogger {
+	n := &namedLogger{name: name, level: zap.NewAtomicLevel()}
+	n.elevation = &elevation{level: &namedLevel{n: n}}
+
+	return n
+}
+
+// build derives the logger of n from global.
+func (n *namedLogger) build(global *Logger) {
+	level := &namedLevel{n: n}
+
+	l := global.Desugar().Named(n.name).WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		return &swapCore{wrap: level.wrap}
+	}))
+	base := &Logger{
+		level:     level,
+		elevation: n.elevation,
+		sampling:  global.sampling,
+	}
+	n.logger.Store(base.derive(l.Sugar()))
+}
+
+// rebuildNamed rebuilds the registered loggers on top of global.
+func rebuildNamed(global *Logger) {
+	_registry.RLock()
+	defer _registry.RUnlock()
+
+	for _, n := range _registry.loggers {
+		n.build(global)
+	}
+}
+
+// namedLevel is the level of a registered logger.
+type namedLevel struct {
+	n *namedLogger
+}
+
+// wrap makes the core of the global logger check the level of the registered
+// logger, see namedCore.
+func (l *namedLevel) wrap(core zapcore.Core) zapcore.Core {
+	if c, ok := core.(*nameLevelCore); ok {
+		return &namedCore{nameLevelCore: c, n: l.n}
+	}
+
+	return core
+}
+
+func (l *namedLevel) parent() dynamicLevel {
+	return _logger.Load().(*globalLogger).base.level
+}
+
+func (l *namedLevel) Enabled(lvl zapcore.Level) bool {
+	if l.n.owned() {
+		return l.n.level.Enabled(lvl)
+	}
+
+	return l.parent().Enabled(lvl)
+}
+
+func (l *namedLevel) Level() zapcore.Level {
+	if l.n.owned() {
+		return l.n.level.Level()
+	}
+
+	return l.parent().Level()
+}
+
+// SetLevel gives the registered logger its own level.
+func (l *namedLevel) SetLevel(lvl zapcore.Level) {
+	l.n.level.SetLevel(lvl)
+	atomic.StoreUint32(&l.n.own, 1)
+}
+
+func (l *namedLevel) following() bool {
+	return !l.n.owned()
+}
+
+// follow makes the registered logger follow the level of the global logger
+// again.
+func (l *namedLevel) follow() {
+	atomic.StoreUint32(&l.n.own, 0)
+}
+
+func (l *namedLevel) parentLevel() zapcore.Level {
+	return l.parent().Level()
+}
+
+func (n *namedLogger) owned() bool {
+	return atomic.LoadUint32(&n.own) == 1
+}
+
+// namedCore checks the entries of a registered logger against its own level
+// once it has one, ignoring the Levels of the global logger, and like the
+// global logger otherwise.
+type namedCore struct {
+	*nameLevelCore
+	n *namedLogger
+}
+
+func (c *namedCore) Enabled(lvl zapcore.Level) bool {
+	if c.n.owned() {
+		return c.n.level.Enabled(lvl)
+	}
+
+	return c.nameLevelCore.Enabled(lvl)
+}
+
+func (c *namedCore) With(fields []zapcore.Field) zapcore.Core {
+	return &namedCore{nameLevelCore: c.nameLevelCore.With(fields).(*nameLevelCore), n: c.n}
+}
+
+func (c *namedCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.n.owned() {
+		return c.nameLevelCore.Check(ent, ce)
+	}
+	if !c.n.level.Enabled(ent.Level) {
+		return ce
+	}
+
+	return c.nameLevelCore.Core.Check(ent, ce)
+}
@@ -0,0 +1,161 @@
+package log
+
+import (
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGet(t *testing.T) {
+	keepGlobal(t)
+
+	config, file := newTestConfig(t)
+	config.Level = InfoLevel
+	config.Name = "main"
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	db := Get("db")
+	if Get("db") != db {
+		t.Error("Get should return the cached logger")
+	}
+	db.Debug("db debug")
+	db.Info("db info")
+
+	SetNamedLevel("http", DebugLevel)
+	Get("http").Debug("http debug")
+	Debug("global debug")
+
+	if names := Names(); !reflect.DeepEqual(names, []string{"db", "http"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+	if _, body := serveLevel(t, LevelHandler(), http.MethodGet, ""); !strings.Contains(body, `"loggers":["db","http"]`) {
+		t.Errorf("registered loggers not listed: %s", body)
+	}
+
+	held := getLogger()
+	config.Name = "reloaded"
+	config.Outputs[0].Destination = file + ".reloaded"
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if Get("db") == db {
+		t.Error("Init should rebuild the registered loggers")
+	}
+	Get("db").Warn("db after init")
+	db.Warn("kept db after init")
+	Get("http").Debug("http still debug")
+	held.Info("held after init")
+
+	db.SetLevel(ErrorLevel)
+	if Get("db").Level() != ErrorLevel || GetLevel() != InfoLevel || Get("http").Level() != DebugLevel {
+		t.Errorf("unexpected levels db %s, global %s, http %s", db.Level(), GetLevel(), Get("http").Level())
+	}
+	serveLevel(t, Get("probe").LevelHandler(), http.MethodPut, `{"level":"warn"}`)
+	if Get("probe").Level() != WarnLevel || GetLevel() != InfoLevel {
+		t.Errorf("the handler of a registered logger should only change its level")
+	}
+	Get("db").Warn("db warn dropped")
+	db.Warn("kept db warn dropped")
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, s := range []string{"[main].db", " - db info\n", " - http debug\n"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("missing %q in %q", s, out)
+		}
+	}
+	for _, s := range []string{"db debug", "global debug", "after init"} {
+		if strings.Contains(out, s) {
+			t.Errorf("unexpected %q in %q", s, out)
+		}
+	}
+
+	data, err = ioutil.ReadFile(file + ".reloaded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out = string(data)
+	for _, s := range []string{" - db after init\n", " - kept db after init\n", " - http still debug\n", " - held after init\n"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("missing %q in %q", s, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasSuffix(line, " - db after init") && !strings.Contains(line, "[reloaded].db ") ||
+			strings.HasSuffix(line, " - kept db after init") && !strings.Contains(line, "[main].db ") {
+			t.Errorf("unexpected logger name in %q", line)
+		}
+	}
+	if strings.Contains(out, "warn dropped") {
+		t.Errorf("db should be at error level: %q", out)
+	}
+}
+
+func TestNamedLevelOverLevels(t *testing.T) {
+	keepGlobal(t)
+
+	config, file := newTestConfig(t)
+	config.Level = InfoLevel
+	config.Levels = "*=info"
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	SetNamedLevel("db", DebugLevel)
+	db := Get("db")
+	if !db.Enabled(DebugLevel) {
+		t.Error("db should be enabled at debug level")
+	}
+	db.Debug("db debug")
+	Get("http").Debug("http debug dropped")
+
+	SetNamedLevel("db", WarnLevel)
+	db.Info("db info dropped")
+
+	out := readFile(t, file)
+	if !strings.Contains(out, " - db debug\n") {
+		t.Errorf("the level of db should win over the Levels rule: %q", out)
+	}
+	if strings.Contains(out, "dropped") {
+		t.Errorf("unexpected entry in %q", out)
+	}
+}
+
+func TestNamedElevation(t *testing.T) {
+	keepGlobal(t)
+
+	config, _ := newTestConfig(t)
+	config.Level = InfoLevel
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	db := Get("db")
+	db.ElevateLevel(DebugLevel, time.Hour)
+	if db.Level() != DebugLevel || GetLevel() != InfoLevel {
+		t.Fatalf("unexpected levels db %s, global %s", db.Level(), GetLevel())
+	}
+	db.CancelElevation()
+
+	// back to following the global level
+	SetLevel(ErrorLevel)
+	if db.Level() != ErrorLevel {
+		t.Errorf("db should follow the global level after the elevation, got %s", db.Level())
+	}
+
+	db.SetLevel(WarnLevel)
+	db.ElevateLevel(DebugLevel, time.Hour)
+	db.CancelElevation()
+	SetLevel(InfoLevel)
+	if db.Level() != WarnLevel {
+		t.Errorf("db should keep its own level after the elevation, got %s", db.Level())
+	}
+}
@@ -0,0 +1,207 @@
+package log
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingConfig keeps the first Initial entries with the same level and
+// message every Tick, and then every Thereafter-th of them, none when
+// Thereafter is 0.
+type SamplingConfig struct {
+	// Tick is the sampling period, one second when unset.
+	Tick       Duration `json:"tick,omitempty" yaml:"tick,omitempty" toml:"tick,omitempty"`
+	Initial    int      `json:"initial" yaml:"initial" toml:"initial"`
+	Thereafter int      `json:"thereafter" yaml:"thereafter" toml:"thereafter"`
+
+	// Levels overrides Initial and Thereafter for some levels, e.g.
+	// {"error": {"initial": 1000, "thereafter": 10}}.
+	Levels map[zapcore.Level]SamplingLevel `json:"levels,omitempty" yaml:"levels,omitempty" toml:"levels,omitempty"`
+
+	// Summary writes an entry with the number of sampled and dropped entries
+	// at most every Summary, along with the first entry sampled once it is
+	// due, when some were dropped. Unset disables the summary.
+	Summary Duration `json:"summary,omitempty" yaml:"summary,omitempty" toml:"summary,omitempty"`
+}
+
+// SamplingLevel is the sampling policy of one level.
+type SamplingLevel struct {
+	Initial    int `json:"initial" yaml:"initial" toml:"initial"`
+	Thereafter int `json:"thereafter" yaml:"thereafter" toml:"thereafter"`
+}
+
+// Duration is a time.Duration written as a string such as "1m30s" in config
+// files.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+	return nil
+}
+
+// SamplingStats counts the entries that went through the sampler of a logger.
+type SamplingStats struct {
+	Sampled uint64 `json:"sampled"`
+	Dropped uint64 `json:"dropped"`
+}
+
+// SamplingStats returns the number of entries sampled and dropped since l was
+// built, zero when sampling is disabled.
+func (l *Logger) SamplingStats() SamplingStats {
+	if l.sampling == nil {
+		return SamplingStats{}
+	}
+
+	return l.sampling.stats()
+}
+
+// GetSamplingStats returns the sampling counters of the global logger.
+func GetSamplingStats() SamplingStats {
+	return getLogger().SamplingStats()
+}
+
+// samplingCounters is shared by the loggers built from the same Config.
+type samplingCounters struct {
+	sampled uint64
+	dropped uint64
+
+	summary time.Duration
+	// next is the time in nanoseconds of the next summary, last holds the
+	// counters at the previous one.
+	next                     int64
+	lastSampled, lastDropped uint64
+	core                     zapcore.Core
+}
+
+func (c *samplingCounters) stats() SamplingStats {
+	return SamplingStats{
+		Sampled: atomic.LoadUint64(&c.sampled),
+		Dropped: atomic.LoadUint64(&c.dropped),
+	}
+}
+
+func (c *samplingCounters) hook(ent zapcore.Entry, dec zapcore.SamplingDecision) {
+	if dec&zapcore.LogDropped != 0 {
+		atomic.AddUint64(&c.dropped, 1)
+		return
+	}
+
+	atomic.AddUint64(&c.sampled, 1)
+	if c.summary > 0 {
+		c.summarize(ent.Time)
+	}
+}
+
+// summarize writes the summary entry of the period ended by now, if due.
+func (c *samplingCounters) summarize(now time.Time) {
+	next := atomic.LoadInt64(&c.next)
+	if now.UnixNano() < next || !atomic.CompareAndSwapInt64(&c.next, next, now.Add(c.summary).UnixNano()) {
+		return
+	}
+
+	s := c.stats()
+	sampled := s.Sampled - atomic.SwapUint64(&c.lastSampled, s.Sampled)
+	dropped := s.Dropped - atomic.SwapUint64(&c.lastDropped, s.Dropped)
+	// the first period starts at the first sampled entry.
+	if next == 0 || dropped == 0 {
+		return
+	}
+
+	ent := zapcore.Entry{Level: zapcore.InfoLevel, Time: now, Message: "sampling summary"}
+	if ce := c.core.Check(ent, nil); ce != nil {
+		ce.Write(
+			zapcore.Field{Key: "sampled", Type: zapcore.Uint64Type, Integer: int64(sampled)},
+			zapcore.Field{Key: "dropped", Type: zapcore.Uint64Type, Integer: int64(dropped)},
+		)
+	}
+}
+
+// sample wraps core in samplers following s, it returns the counters they
+// feed.
+func (s *SamplingConfig) sample(core zapcore.Core) (zapcore.Core, *samplingCounters) {
+	tick := time.Duration(s.Tick)
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	c := &samplingCounters{summary: time.Duration(s.Summary), core: core}
+	hook := zapcore.SamplerHook(c.hook)
+	if len(s.Levels) == 0 {
+		return newSampler(core, tick, s.Initial, s.Thereafter, hook), c
+	}
+
+	sampler := &levelSampler{
+		Core:   newSampler(core, tick, s.Initial, s.Thereafter, hook),
+		levels: make(map[zapcore.Level]zapcore.Core, len(s.Levels)),
+	}
+	for level, l := range s.Levels {
+		sampler.levels[level] = newSampler(core, tick, l.Initial, l.Thereafter, hook)
+	}
+
+	return sampler, c
+}
+
+func newSampler(core zapcore.Core, tick time.Duration, first, thereafter int, hook zapcore.SamplerOption) zapcore.Core {
+	if thereafter == 0 {
+		// zapcore divides by thereafter, never reach the next multiple instead.
+		thereafter = math.MaxInt32
+	}
+
+	return zapcore.NewSamplerWithOptions(core, tick, first, thereafter, hook)
+}
+
+func (s *SamplingConfig) validate() error {
+	var errs error
+
+	if s.Tick < 0 || s.Summary < 0 {
+		errs = multierr.Append(errs, fmt.Errorf("sampling tick and summary must not be negative"))
+	}
+	if s.Initial < 0 || s.Thereafter < 0 {
+		errs = multierr.Append(errs, fmt.Errorf("sampling initial and thereafter must not be negative"))
+	}
+	for level, l := range s.Levels {
+		if l.Initial < 0 || l.Thereafter < 0 {
+			errs = multierr.Append(errs, fmt.Errorf("sampling %s initial and thereafter must not be negative", level))
+		}
+	}
+
+	return errs
+}
+
+// levelSampler routes the entries of the levels with their own policy to
+// their sampler, the others to the embedded one.
+type levelSampler struct {
+	zapcore.Core
+	levels map[zapcore.Level]zapcore.Core
+}
+
+func (s *levelSampler) With(fields []zapcore.Field) zapcore.Core {
+	levels := make(map[zapcore.Level]zapcore.Core, len(s.levels))
+	for level, core := range s.levels {
+		levels[level] = core.With(fields)
+	}
+
+	return &levelSampler{Core: s.Core.With(fields), levels: levels}
+}
+
+func (s *levelSampler) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if core, ok := s.levels[ent.Level]; ok {
+		return core.Check(ent, ce)
+	}
+
+	return s.Core.Check(ent, ce)
+}
@@ -0,0 +1,115 @@
+package log
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSampling(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+
+	config, err := LoadConfig(writeConfigFile(t, "log.yaml", `
+crashLogFilename: ""
+outputs:
+  - destination: `+file+`
+sampling:
+  tick: 1m
+  initial: 2
+  thereafter: 0
+  summary: 1h
+  levels:
+    error:
+      initial: 5
+      thereafter: 5
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := config.Sampling; time.Duration(s.Tick) != time.Minute || s.Levels[ErrorLevel].Thereafter != 5 {
+		t.Fatalf("unexpected sampling config %+v", s)
+	}
+
+	l, err := config.logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the entries are written with their time, within a tick, rather than
+	// depending on the pace of the test.
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	write := func(level zapcore.Level, msg string, at time.Time) {
+		if ce := l.Desugar().Core().Check(zapcore.Entry{Level: level, Time: at, Message: msg}, nil); ce != nil {
+			ce.Write()
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		write(InfoLevel, "info message", start)
+		write(ErrorLevel, "error message", start)
+	}
+	if s := l.With("k", "v").SamplingStats(); s != (SamplingStats{Sampled: 2 + 6, Dropped: 8 + 4}) {
+		t.Errorf("unexpected stats %+v", s)
+	}
+	if strings.Contains(readFile(t, file), "sampling summary") {
+		t.Error("the summary should wait for the end of the period")
+	}
+
+	write(WarnLevel, "summary trigger", start.Add(2*time.Hour))
+
+	out := readFile(t, file)
+	if n := strings.Count(out, " - info message\n"); n != 2 {
+		t.Errorf("expect 2 info entries, got %d", n)
+	}
+	if n := strings.Count(out, " - error message\n"); n != 6 {
+		t.Errorf("expect 6 error entries, got %d", n)
+	}
+	if !strings.Contains(out, "[INFO] {sampled=8, dropped=12} - sampling summary\n") {
+		t.Errorf("missing summary in %q", out)
+	}
+}
+
+func readFile(t *testing.T, file string) string {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestSamplingDisabled(t *testing.T) {
+	l, err := NewDevelopmentConfig().logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info("message")
+	if s := l.SamplingStats(); s != (SamplingStats{}) {
+		t.Errorf("expect no stats without sampling, got %+v", s)
+	}
+}
+
+func TestSamplingValidate(t *testing.T) {
+	config := NewLogConfig()
+	config.Sampling = &SamplingConfig{
+		Tick:   Duration(-time.Second),
+		Levels: map[zapcore.Level]SamplingLevel{WarnLevel: {Initial: -1}},
+	}
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, s := range []string{"sampling tick", "sampling warn"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("error does not report %s: %v", s, err)
+		}
+	}
+}
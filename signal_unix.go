@@ -0,0 +1,76 @@
+//go:build !windows
+// +build !windows
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// HandleSignals installs a handler for the global logger: SIGUSR1 switches the
+// level to debug and the next SIGUSR1 switches it back, SIGHUP reopens every
+// log file so that external rotation (logrotate, copytruncate) is picked up.
+// Each transition is logged. Call remove to uninstall the handler.
+func HandleSignals() (remove func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		// the level to return to on the next SIGUSR1
+		back := InfoLevel
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-ch:
+				switch sig {
+				case syscall.SIGUSR1:
+					back = cycleLevel(back)
+				case syscall.SIGHUP:
+					n := reopenFiles()
+					Infof("reopened %d log files on %s", n, sig)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+// cycleLevel switches the global logger to debug, or to back when it already
+// is at debug, and returns the level to switch back to next time.
+func cycleLevel(back zapcore.Level) zapcore.Level {
+	from, to := GetLevel(), DebugLevel
+	if from == DebugLevel {
+		to = back
+	} else {
+		back = from
+	}
+
+	// log while the more verbose of both levels is active
+	if to > from {
+		Infof("log level changed from %s to %s on %s", from, to, syscall.SIGUSR1)
+		SetLevel(to)
+	} else {
+		SetLevel(to)
+		Infof("log level changed from %s to %s on %s", from, to, syscall.SIGUSR1)
+	}
+
+	return back
+}
@@ -0,0 +1,52 @@
+//go:build !windows
+// +build !windows
+
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestHandleSignals(t *testing.T) {
+	keepGlobal(t)
+
+	config, file := newTestConfig(t)
+	config.Level = WarnLevel
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	remove := HandleSignals()
+	defer remove()
+
+	_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+	waitFor(t, func() bool { return GetLevel() == DebugLevel })
+	_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+	waitFor(t, func() bool { return GetLevel() == WarnLevel })
+
+	Warn("before rotation")
+	if err := os.Rename(file, file+".1"); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	waitFor(t, func() bool {
+		Warn("after rotation")
+		_, err := os.Stat(file)
+		return err == nil
+	})
+
+	data, err := ioutil.ReadFile(file + ".1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 {
+		t.Error("rotated file should keep the entries written before SIGHUP")
+	}
+
+	remove()
+	remove()
+}
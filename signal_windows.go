@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package log
+
+// HandleSignals is a no-op on windows, which has neither SIGUSR1 nor SIGHUP.
+func HandleSignals() (remove func()) {
+	return func() {}
+}
@@ -0,0 +1,169 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// _slogSkip skips the frames of slogHandler.Handle and of the slog.Logger
+// methods calling it, so that stacktraces start at the caller.
+const _slogSkip = 3
+
+// SlogHandler returns a slog.Handler writing to the cores of the global logger
+// installed at call time, see Logger.SlogHandler.
+func SlogHandler() slog.Handler {
+	return getLogger().SlogHandler()
+}
+
+// SlogHandler returns a slog.Handler writing to the cores of l, with its
+// format, outputs and levels. Groups are rendered as namespaces.
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{logger: l.Desugar().WithOptions(zap.AddCallerSkip(_slogSkip))}
+}
+
+type slogHandler struct {
+	logger *zap.Logger
+	// groups are opened by the next attributes only, slog drops empty groups.
+	groups []string
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.Core().Enabled(zapLevel(level))
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	ce := h.logger.Check(zapLevel(r.Level), r.Message)
+	if ce == nil {
+		return nil
+	}
+
+	if !r.Time.IsZero() {
+		ce.Entry.Time = r.Time
+	}
+	if ce.Entry.Caller.Defined && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ce.Entry.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+	}
+
+	fields := make([]zapcore.Field, 0, len(h.groups)+r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, a)
+		return true
+	})
+	if len(fields) > 0 {
+		fields = append(namespaces(h.groups), fields...)
+	}
+
+	ce.Write(fields...)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []zapcore.Field
+	for _, a := range attrs {
+		fields = appendAttr(fields, a)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+
+	return &slogHandler{logger: h.logger.With(append(namespaces(h.groups), fields...)...)}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+	return &slogHandler{logger: h.logger, groups: append(groups, name)}
+}
+
+// zapLevel maps level to the closest zapcore level at or below it, slog has no
+// level panicking or exiting.
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+func namespaces(groups []string) []zapcore.Field {
+	fields := make([]zapcore.Field, len(groups))
+	for i, g := range groups {
+		fields[i] = zap.Namespace(g)
+	}
+
+	return fields
+}
+
+func appendAttr(fields []zapcore.Field, a slog.Attr) []zapcore.Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	switch v := a.Value; v.Kind() {
+	case slog.KindGroup:
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+		if a.Key == "" {
+			for _, ga := range attrs {
+				fields = appendAttr(fields, ga)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(a.Key, slogGroup(attrs)))
+	case slog.KindString:
+		return append(fields, zap.String(a.Key, v.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(a.Key, v.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(a.Key, v.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(a.Key, v.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(a.Key, v.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Stringer(a.Key, v.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.String(a.Key, v.Time().Format(time.RFC3339Nano)))
+	}
+
+	if err, ok := a.Value.Any().(error); ok {
+		return append(fields, zap.NamedError(a.Key, err))
+	}
+	return append(fields, zap.Any(a.Key, a.Value.Any()))
+}
+
+// slogGroup marshals the attributes of a group as an object.
+type slogGroup []slog.Attr
+
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	var fields []zapcore.Field
+	for _, a := range g {
+		fields = appendAttr(fields, a)
+	}
+	for i := range fields {
+		fields[i].AddTo(enc)
+	}
+
+	return nil
+}
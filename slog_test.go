@@ -0,0 +1,78 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSlogHandler(t *testing.T) {
+	config, file := newTestConfig(t)
+	config.Level = InfoLevel
+	l, err := config.logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(l.SlogHandler())
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("debug should be disabled")
+	}
+	logger.Debug("debug message")
+
+	line := callerLine(t)
+	logger.Info("info message", "k", "v", "n", 1, "ok", true)
+	logger.With("app", "demo").WithGroup("req").Warn("warn message", "id", 7, slog.Group("user", "name", "bob"))
+	logger.WithGroup("empty").Error("error message", "err", errors.New("boom"))
+	logger.WithGroup("unused").Info("no attrs")
+
+	l.SetLevel(DebugLevel)
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("debug should follow the atomic level")
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, s := range []string{
+		"[INFO]" + line + "{k=v, n=1, ok=true} - info message\n",
+		"[WARN]",
+		" {app=demo, req={id=7, user={name=bob}}} - warn message\n",
+		"[ERRO]",
+		" {empty={err=boom}} - error message\n",
+		" - no attrs\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("missing %q in %q", s, out)
+		}
+	}
+	if strings.Contains(out, "debug message") {
+		t.Errorf("debug entry should be dropped: %q", out)
+	}
+}
+
+func TestZapLevel(t *testing.T) {
+	for level, want := range map[slog.Level]zapcore.Level{
+		slog.LevelDebug - 4: DebugLevel,
+		slog.LevelDebug:     DebugLevel,
+		slog.LevelInfo:      InfoLevel,
+		slog.LevelInfo + 2:  InfoLevel,
+		slog.LevelWarn:      WarnLevel,
+		slog.LevelError:     ErrorLevel,
+		slog.LevelError + 4: ErrorLevel,
+	} {
+		if got := zapLevel(level); got != want {
+			t.Errorf("zapLevel(%s) = %s, want %s", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"bytes"
+	stdlog "log"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// _stdLogSkip skips the frames of stdWriter.Write and of the standard library
+// logger, e.g. Printf and the output method it calls, so that entries are
+// attributed to the callers of the standard library functions.
+const _stdLogSkip = 3
+
+// RedirectStdLog sends the output of the standard library log package to the
+// global logger at level, following later calls to Init. It returns a
+// function restoring the previous output, flags and prefix.
+func RedirectStdLog(level zapcore.Level) (restore func()) {
+	flags, prefix, out := stdlog.Flags(), stdlog.Prefix(), stdlog.Writer()
+
+	stdlog.SetFlags(0)
+	stdlog.SetPrefix("")
+	stdlog.SetOutput(&stdWriter{logger: getLogger, level: level})
+
+	return func() {
+		stdlog.SetFlags(flags)
+		stdlog.SetPrefix(prefix)
+		stdlog.SetOutput(out)
+	}
+}
+
+// NewStdLog returns a standard library *log.Logger writing to l at level, for
+// the libraries taking one, e.g. http.Server.ErrorLog.
+func NewStdLog(l *Logger, level zapcore.Level) *stdlog.Logger {
+	return stdlog.New(&stdWriter{logger: func() *Logger { return l }, level: level}, "", 0)
+}
+
+type stdWriter struct {
+	logger func() *Logger
+	level  zapcore.Level
+
+	skip atomic.Value // *stdSkip
+}
+
+// stdSkip is the logger returned by stdWriter.logger, with _stdLogSkip frames
+// skipped. It is only rebuilt when the logger changes, e.g. on Init.
+type stdSkip struct {
+	src *Logger
+	*zap.Logger
+}
+
+func (w *stdWriter) Write(p []byte) (int, error) {
+	src := w.logger()
+	l, ok := w.skip.Load().(*stdSkip)
+	if !ok || l.src != src {
+		l = &stdSkip{src: src, Logger: src.Desugar().WithOptions(zap.AddCallerSkip(_stdLogSkip))}
+		w.skip.Store(l)
+	}
+
+	if ce := l.Check(w.level, string(bytes.TrimSuffix(p, []byte("\n")))); ce != nil {
+		ce.Write()
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,71 @@
+package log
+
+import (
+	"io/ioutil"
+	stdlog "log"
+	"strings"
+	"testing"
+)
+
+func TestRedirectStdLog(t *testing.T) {
+	keepGlobal(t)
+
+	config, file := newTestConfig(t)
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	flags, prefix := stdlog.Flags(), stdlog.Prefix()
+	restore := RedirectStdLog(WarnLevel)
+
+	var want []string
+	want = append(want, callerLine(t))
+	stdlog.Printf("redirected %s", "printf")
+	want = append(want, callerLine(t))
+	stdlog.Println("redirected println")
+
+	l, err := config.logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, callerLine(t))
+	NewStdLog(l, ErrorLevel).Print("std logger")
+
+	restore()
+	if stdlog.Flags() != flags || stdlog.Prefix() != prefix {
+		t.Error("restore should reset the flags and prefix")
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for i, s := range []string{
+		"[WARN]" + want[0] + "- redirected printf\n",
+		"[WARN]" + want[1] + "- redirected println\n",
+		"[ERRO]" + want[2] + "- std logger\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("%d: missing %q in %q", i, s, out)
+		}
+	}
+}
+
+func TestStdWriterRebuild(t *testing.T) {
+	keepGlobal(t)
+
+	w := &stdWriter{logger: getLogger, level: DebugLevel}
+	_, _ = w.Write([]byte("first\n"))
+	first := w.skip.Load()
+	_, _ = w.Write([]byte("second\n"))
+	if w.skip.Load() != first {
+		t.Error("the logger should be kept while the global logger is unchanged")
+	}
+
+	Fields("k", "v")
+	_, _ = w.Write([]byte("third\n"))
+	if w.skip.Load() == first {
+		t.Error("the logger should follow the global logger")
+	}
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// _root holds the *rootCore of the global logger, the loggers derived from the
+// global logger write to it through a swapCore.
+var _root atomic.Value
+
+// rootCore is the core built from the Config of the global logger.
+type rootCore struct {
+	zapcore.Core
+}
+
+// swapCore writes to the core of the global logger in place when the entry is
+// logged, so that the loggers kept across Init or a configuration reload
+// follow the new destinations instead of writing to closed files.
+type swapCore struct {
+	// wrap adapts the root core, e.g. to the level of a registered logger.
+	wrap   func(zapcore.Core) zapcore.Core
+	fields []zapcore.Field
+
+	built atomic.Value // *swapBuild
+}
+
+// swapBuild caches the core of a swapCore built on top of root.
+type swapBuild struct {
+	root *rootCore
+	core zapcore.Core
+}
+
+func (c *swapCore) current() zapcore.Core {
+	root := _root.Load().(*rootCore)
+	if b, ok := c.built.Load().(*swapBuild); ok && b.root == root {
+		return b.core
+	}
+
+	core := root.Core
+	if c.wrap != nil {
+		core = c.wrap(core)
+	}
+	if len(c.fields) > 0 {
+		core = core.With(c.fields)
+	}
+
+	c.built.Store(&swapBuild{root: root, core: core})
+	return core
+}
+
+func (c *swapCore) Enabled(lvl zapcore.Level) bool {
+	return c.current().Enabled(lvl)
+}
+
+func (c *swapCore) With(fields []zapcore.Field) zapcore.Core {
+	return &swapCore{
+		wrap:   c.wrap,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *swapCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return c.current().Check(ent, ce)
+}
+
+func (c *swapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.current().Write(ent, fields)
+}
+
+func (c *swapCore) Sync() error {
+	return c.current().Sync()
+}
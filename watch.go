@@ -0,0 +1,152 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// WatchConfig loads file with LoadConfig, initializes the global logger with it
+// and then polls file every interval (5s when interval <= 0) for changes.
+//
+// A changed Level or Levels is applied to the running logger in place.
+// Any other change rebuilds the cores and swaps the global logger atomically,
+// the files of the previous configuration are closed only after the swap. The
+// loggers derived from the global logger, e.g. by Get, Named or FromContext,
+// write to the new destinations even when kept by their callers. When
+// the reloaded file is invalid the error is logged and the previous
+// configuration stays in use. Call stop to end the polling.
+func WatchConfig(file string, interval time.Duration) (stop func(), err error) {
+	config, err := LoadConfig(file)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := config.logger()
+	if err != nil {
+		return nil, err
+	}
+	setLogger(l)
+
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	w := &configWatcher{
+		file:    file,
+		config:  config,
+		logger:  l,
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		done:    make(chan struct{}),
+	}
+	go w.run(interval)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(w.done) })
+	}, nil
+}
+
+type configWatcher struct {
+	file    string
+	config  *Config
+	logger  *Logger // the global logger built from config
+	modTime time.Time
+	size    int64
+	done    chan struct{}
+}
+
+func (w *configWatcher) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+func (w *configWatcher) check() {
+	info, err := os.Stat(w.file)
+	if err != nil {
+		getLogger().Errorf("watch log config %s error. %v", w.file, err)
+		return
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+
+	config, err := LoadConfig(w.file)
+	if err != nil {
+		getLogger().Errorf("reload log config error, keep the previous configuration. %v", err)
+		return
+	}
+
+	w.apply(config)
+}
+
+func (w *configWatcher) apply(config *Config) {
+	old := w.config
+
+	if old.sameCores(config) {
+		// through the logger, so that an active elevation ends rather than
+		// reverting the level later on.
+		if old.Level != config.Level {
+			old.Level = config.Level
+			w.logger.SetLevel(config.Level)
+			getLogger().Infof("log level changed to %s by %s", config.Level, w.file)
+		}
+		if old.Levels != config.Levels {
+			old.Levels = config.Levels
+			_ = w.logger.SetLevels(config.Levels)
+			getLogger().Infof("log levels changed to %q by %s", config.Levels, w.file)
+		}
+		return
+	}
+
+	l, err := config.logger()
+	if err != nil {
+		getLogger().Errorf("reload log config error, keep the previous configuration. %v", err)
+		return
+	}
+	setLogger(l)
+	w.config, w.logger = config, l
+
+	for _, writer := range old.writers {
+		_ = writer.Close()
+	}
+
+	getLogger().Infof("log config reloaded from %s", w.file)
+}
+
+// sameCores reports whether config and other only differ in their levels, in
+// which case the cores built from config can be kept.
+func (config *Config) sameCores(other *Config) bool {
+	a, b := *config, *other
+	a.Level, a.Levels = b.Level, b.Levels
+
+	ja, err := json.Marshal(&a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(&b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(ja, jb) && reflect.DeepEqual(a.Fields, b.Fields)
+}
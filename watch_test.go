@@ -0,0 +1,140 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	keepGlobal(t)
+
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "log.yaml")
+	// replace the file atomically so that the watcher never sees a partial write
+	write := func(content string) {
+		tmp := file + ".tmp"
+		if err := ioutil.WriteFile(tmp, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := "crashLogFilename: \"\"\nerrorLogFilename: \"\"\n"
+
+	write(base + "level: info\nfilename: " + filepath.Join(dir, "a.log") + "\n")
+	stop, err := WatchConfig(file, 5*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	logger := getLogger()
+	core := logger.Desugar().Core()
+	if core.Enabled(DebugLevel) {
+		t.Fatal("debug should be disabled")
+	}
+
+	// level only change keeps the cores
+	write(base + "level: debug\nfilename: " + filepath.Join(dir, "a.log") + "\n")
+	waitFor(t, func() bool { return core.Enabled(DebugLevel) })
+	if getLogger() != logger {
+		t.Error("level change should not rebuild the cores")
+	}
+
+	// invalid file keeps the previous configuration
+	write(base + "level: verbose\nfilename: " + filepath.Join(dir, "a.log") + "\n")
+	time.Sleep(50 * time.Millisecond)
+	if getLogger() != logger || !core.Enabled(DebugLevel) {
+		t.Error("invalid config should keep the previous logger")
+	}
+
+	// file change swaps the logger, the loggers kept meanwhile follow it
+	held, named, std := logger.Named("held"), Get("watched"), NewStdLog(logger, InfoLevel)
+	write(base + "level: debug\nfilename: " + filepath.Join(dir, "b.log") + "\n")
+	waitFor(t, func() bool { return getLogger() != logger })
+
+	Infof("written to %s", "b.log")
+	held.Info("held written to b.log")
+	named.Info("named written to b.log")
+	std.Print("std written to b.log")
+	data, err := ioutil.ReadFile(filepath.Join(dir, "b.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"written to b.log", "held written", "named written", "std written"} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("missing %q in %q", s, data)
+		}
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, "a.log")); strings.Contains(string(data), "b.log") {
+		t.Errorf("entries written to the previous file: %q", data)
+	}
+}
+
+func TestFileWriterWriteAfterClose(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+
+	config := NewLogConfig()
+	config.fileWriteSyncer(file, nil)
+	w := config.writers[0]
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.Write([]byte("late entry\n")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := ioutil.ReadFile(file); string(data) != "late entry\n" {
+		t.Errorf("unexpected content %q", data)
+	}
+
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("no /proc to list the open files")
+	}
+	for _, fd := range fds {
+		if target, _ := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); target == file {
+			t.Errorf("%s left open by a write after Close", file)
+		}
+	}
+}
+
+func TestWatchConfigDuringElevation(t *testing.T) {
+	keepGlobal(t)
+
+	file := writeConfigFile(t, "log.yaml", "crashLogFilename: \"\"\nerrorLogFilename: \"\"\nfilename: \"\"\nlevel: info\n")
+	stop, err := WatchConfig(file, 5*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	ElevateLevel(DebugLevel, time.Hour)
+	// a distinct size, the modification time may not change in time
+	if err := ioutil.WriteFile(file, []byte("crashLogFilename: \"\"\nerrorLogFilename: \"\"\nfilename: \"\"\nlevel: error\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return GetLevel() == ErrorLevel })
+
+	if _, ok := getLogger().ElevatedUntil(); ok {
+		t.Error("a level from the file should end the elevation")
+	}
+}